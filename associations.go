@@ -0,0 +1,202 @@
+package generics
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// JoinMany loads a has-many association, the reverse of Join. For a
+// collection of accounts and a list of payments it sets Account.Payments to
+// the payments whose AccountID is the account's ID.
+func (c *Collection) JoinMany(list interface{}) *Collection {
+	return mustCollection(c.JoinManyE(list))
+}
+
+func (c *Collection) JoinManyE(list interface{}) (*Collection, error) {
+	own, other, err := associationNames(c.collection, list)
+	if err != nil {
+		return c, err
+	}
+	return c.JoinManyGenericE(list, other+"s", own+"ID", "ID")
+}
+
+func (c *Collection) JoinManyGeneric(list interface{}, name, foreignKeyName, primaryKeyName string) *Collection {
+	return mustCollection(c.JoinManyGenericE(list, name, foreignKeyName, primaryKeyName))
+}
+
+// JoinManyGenericE sets the slice field name of every element of the
+// collection to the elements of list whose foreign key matches the element's
+// primary key. Elements without any are set to nil. Foreign keys are
+// converted and nil or NULL keys skipped as for JoinGenericWithReport,
+// composite keys are given as comma separated attributes.
+func (c *Collection) JoinManyGenericE(list interface{}, name, foreignKeyName, primaryKeyName string) (*Collection, error) {
+	return c.joinList(list, name, foreignKeyName, primaryKeyName, true)
+}
+
+// JoinOne loads a has-one association. For a collection of accounts and a
+// list of profiles it sets Account.Profile to the profile whose AccountID is
+// the account's ID.
+func (c *Collection) JoinOne(list interface{}) *Collection {
+	return mustCollection(c.JoinOneE(list))
+}
+
+func (c *Collection) JoinOneE(list interface{}) (*Collection, error) {
+	own, other, err := associationNames(c.collection, list)
+	if err != nil {
+		return c, err
+	}
+	return c.JoinOneGenericE(list, other, own+"ID", "ID")
+}
+
+func (c *Collection) JoinOneGeneric(list interface{}, name, foreignKeyName, primaryKeyName string) *Collection {
+	return mustCollection(c.JoinOneGenericE(list, name, foreignKeyName, primaryKeyName))
+}
+
+// JoinOneGenericE sets the field name of every element of the collection to
+// the element of list whose foreign key matches the element's primary key,
+// the last one if there are several.
+func (c *Collection) JoinOneGenericE(list interface{}, name, foreignKeyName, primaryKeyName string) (*Collection, error) {
+	return c.joinList(list, name, foreignKeyName, primaryKeyName, false)
+}
+
+func (c *Collection) joinList(list interface{}, name, foreignKeyName, primaryKeyName string, many bool) (res *Collection, err error) {
+	defer catch(&err)
+	sv, err := sliceValue(c.collection)
+	if err != nil {
+		return c, err
+	}
+	lv, err := sliceValue(list)
+	if err != nil {
+		return c, err
+	}
+	foreignKeys, primaryKeys, err := keyPair(sv.Type(), foreignKeyName, primaryKeyName)
+	if err != nil {
+		return c, err
+	}
+	keyType, _, err := baseKeyGetter(sv.Type().Elem(), primaryKeys)
+	if err != nil {
+		return c, err
+	}
+	// foreign keys are converted to the primary key type, null keys are skipped
+	key, err := matchingKeyGetter(lv.Type().Elem(), foreignKeys, keyType)
+	if err != nil {
+		return c, err
+	}
+	m := indexByKey(lv, keyType, key, many)
+	_, err = setFromMap(sv, m, name, primaryKeys)
+	return c, err
+}
+
+// JoinThrough loads a many-to-many association through a join table. For a
+// collection of accounts, a through list of account tags and a list of tags
+// it sets Account.Tags to the tags whose ID is the TagID of an account tag
+// with the account's ID as AccountID.
+func (c *Collection) JoinThrough(through, list interface{}) *Collection {
+	return mustCollection(c.JoinThroughE(through, list))
+}
+
+func (c *Collection) JoinThroughE(through, list interface{}) (*Collection, error) {
+	own, other, err := associationNames(c.collection, list)
+	if err != nil {
+		return c, err
+	}
+	return c.JoinThroughGenericE(through, list, other+"s", own+"ID", other+"ID", "ID", "ID")
+}
+
+func (c *Collection) JoinThroughGeneric(through, list interface{}, name, foreignKeyName, otherKeyName, primaryKeyName, otherPrimaryKeyName string) *Collection {
+	return mustCollection(c.JoinThroughGenericE(through, list, name, foreignKeyName, otherKeyName, primaryKeyName, otherPrimaryKeyName))
+}
+
+// JoinThroughGenericE sets the slice field name of every element of the
+// collection to the elements of list linked to it by through. foreignKeyName
+// on through refers to primaryKeyName of the collection, otherKeyName on
+// through to otherPrimaryKeyName of list. Keys on through are converted as
+// for JoinGenericWithReport. Links with a nil or NULL key or to missing
+// elements of list are skipped.
+func (c *Collection) JoinThroughGenericE(through, list interface{}, name, foreignKeyName, otherKeyName, primaryKeyName, otherPrimaryKeyName string) (res *Collection, err error) {
+	defer catch(&err)
+	sv, err := sliceValue(c.collection)
+	if err != nil {
+		return c, err
+	}
+	tv, err := sliceValue(through)
+	if err != nil {
+		return c, err
+	}
+	foreignKeys, primaryKeys, err := keyPair(sv.Type(), foreignKeyName, primaryKeyName)
+	if err != nil {
+		return c, err
+	}
+	otherKeys, otherPrimaryKeys, err := keyPair(tv.Type(), otherKeyName, otherPrimaryKeyName)
+	if err != nil {
+		return c, err
+	}
+
+	lv, err := sliceValue(list)
+	if err != nil {
+		return c, err
+	}
+	indexType, index, err := baseKeyGetter(lv.Type().Elem(), otherPrimaryKeys)
+	if err != nil {
+		return c, err
+	}
+	iv := indexByKey(lv, indexType, index, false)
+	other, err := matchingKeyGetter(tv.Type().Elem(), otherKeys, iv.Type().Key())
+	if err != nil {
+		return c, err
+	}
+	ownType, _, err := baseKeyGetter(sv.Type().Elem(), primaryKeys)
+	if err != nil {
+		return c, err
+	}
+	own, err := matchingKeyGetter(tv.Type().Elem(), foreignKeys, ownType)
+	if err != nil {
+		return c, err
+	}
+
+	m := reflect.MakeMap(reflect.MapOf(ownType, reflect.SliceOf(iv.Type().Elem())))
+	for i := 0; i < tv.Len(); i++ {
+		link := tv.Index(i)
+		key, _ := other(link)
+		k, _ := own(link)
+		if !key.IsValid() || !k.IsValid() {
+			continue
+		}
+		el := iv.MapIndex(key)
+		if !el.IsValid() {
+			continue
+		}
+		list := m.MapIndex(k)
+		if !list.IsValid() {
+			list = reflect.MakeSlice(m.Type().Elem(), 0, 1)
+		}
+		m.SetMapIndex(k, reflect.Append(list, el))
+	}
+	_, err = setFromMap(sv, m, name, primaryKeys)
+	return c, err
+}
+
+// associationNames returns the element type names of the collection and
+// list, the base of Join's naming convention.
+func associationNames(collection, list interface{}) (own, other string, err error) {
+	sv, err := sliceValue(collection)
+	if err != nil {
+		return "", "", err
+	}
+	t := reflect.TypeOf(list)
+	if t == nil || t.Kind() != reflect.Slice {
+		return "", "", unsupportedType(t, "expected slice")
+	}
+	return nameFromMap(sv.Type()), nameFromMap(t), nil
+}
+
+// keyPair splits the comma separated foreign and primary key names, which
+// must have the same length.
+func keyPair(t reflect.Type, foreignKeyName, primaryKeyName string) (foreignKeys, primaryKeys []interface{}, err error) {
+	foreignKeys, primaryKeys = splitKeys(foreignKeyName), splitKeys(primaryKeyName)
+	if len(foreignKeys) != len(primaryKeys) {
+		return nil, nil, &Error{Kind: ErrBadSignature, Type: t,
+			Msg: fmt.Sprintf("foreign key %s and primary key %s differ in length", foreignKeyName, primaryKeyName)}
+	}
+	return foreignKeys, primaryKeys, nil
+}
@@ -0,0 +1,177 @@
+package generics
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type Owner struct {
+	ID       int
+	Region   string
+	Pet      *Pet
+	Pets     []*Pet
+	Toys     []*Toy
+	Favorite []*Toy
+}
+
+type Pet struct {
+	ID      int
+	OwnerID int
+	Region  string
+	Name    string
+}
+
+type Toy struct {
+	ID   int
+	Name string
+}
+
+type OwnerToy struct {
+	OwnerID int
+	ToyID   int
+}
+
+func owners() []*Owner {
+	return []*Owner{{ID: 1, Region: "eu"}, {ID: 2, Region: "us"}, {ID: 3, Region: "eu"}}
+}
+
+func petNames(pets []*Pet) string {
+	return fmt.Sprint(Map(pets, "Name"))
+}
+
+func TestJoinMany(t *testing.T) {
+	pets := []*Pet{
+		{ID: 1, OwnerID: 1, Region: "eu", Name: "Rex"},
+		{ID: 2, OwnerID: 2, Region: "us", Name: "Tom"},
+		{ID: 3, OwnerID: 1, Region: "us", Name: "Kit"},
+	}
+	many := owners()
+	New(many).JoinMany(pets)
+	composite := owners()
+	New(composite).JoinManyGeneric(pets, "Pets", "OwnerID,Region", "ID,Region")
+	one := owners()
+	New(one).JoinOne(pets)
+
+	tests := []struct{ Has, Want interface{} }{
+		{petNames(many[0].Pets), "[Rex Kit]"},
+		{petNames(many[1].Pets), "[Tom]"},
+		{many[2].Pets == nil, true},
+		{petNames(composite[0].Pets), "[Rex]"},
+		{petNames(composite[1].Pets), "[Tom]"},
+		{one[0].Pet.Name, "Kit"},
+		{one[1].Pet.Name, "Tom"},
+		{one[2].Pet == nil, true},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestJoinThrough(t *testing.T) {
+	toys := []*Toy{{1, "Ball"}, {2, "Bone"}, {3, "Rope"}}
+	links := []*OwnerToy{{1, 1}, {1, 3}, {2, 3}, {2, 4}}
+	list := owners()
+	New(list).JoinThrough(links, toys)
+	favorites := owners()
+	New(favorites).JoinThroughGeneric(links, toys, "Favorite", "OwnerID", "ToyID", "ID", "ID")
+
+	tests := []struct{ Has, Want interface{} }{
+		{fmt.Sprint(Map(list[0].Toys, "Name")), "[Ball Rope]"},
+		{fmt.Sprint(Map(list[1].Toys, "Name")), "[Rope]"},
+		{list[2].Toys == nil, true},
+		{fmt.Sprint(Map(favorites[0].Favorite, "Name")), "[Ball Rope]"},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestJoinNullableForeignKeys(t *testing.T) {
+	type pet struct {
+		OwnerID sql.NullInt64
+		Name    string
+	}
+	type link struct {
+		OwnerID *int
+		ToyID   sql.NullInt64
+	}
+	type owner struct {
+		ID   int
+		Pets []*pet `generics:"has_many,fk=OwnerID"`
+		Pet  *pet   `generics:"has_one,fk=OwnerID"`
+		Toys []*Toy `generics:"many_to_many,through=link,fk=OwnerID,other=ToyID"`
+	}
+	one, two := 1, 2
+	pets := []*pet{
+		{sql.NullInt64{Int64: 1, Valid: true}, "Rex"},
+		{sql.NullInt64{}, "Stray"},
+		{sql.NullInt64{Int64: 1, Valid: true}, "Kit"},
+		{sql.NullInt64{Int64: 2, Valid: true}, "Tom"},
+	}
+	toys := []*Toy{{1, "Ball"}, {2, "Bone"}}
+	links := []*link{
+		{&one, sql.NullInt64{Int64: 1, Valid: true}},
+		{nil, sql.NullInt64{Int64: 2, Valid: true}},
+		{&one, sql.NullInt64{}},
+		{&two, sql.NullInt64{Int64: 2, Valid: true}},
+	}
+	list := []*owner{{ID: 1}, {ID: 2}, {ID: 3}}
+	_, errMany := New(list).JoinManyGenericE(pets, "Pets", "OwnerID", "ID")
+	_, errOne := New(list).JoinOneGenericE(pets, "Pet", "OwnerID", "ID")
+	_, errThrough := New(list).JoinThroughGenericE(links, toys, "Toys", "OwnerID", "ToyID", "ID", "ID")
+	tagged := []*owner{{ID: 1}, {ID: 2}}
+	errAll := JoinAllE(tagged, pets, links, toys)
+
+	names := func(pets []*pet) string { return fmt.Sprint(Map(pets, "Name")) }
+	tests := []struct{ Has, Want interface{} }{
+		{errMany, nil},
+		{errOne, nil},
+		{errThrough, nil},
+		{errAll, nil},
+		{names(list[0].Pets), "[Rex Kit]"},
+		{names(list[1].Pets), "[Tom]"},
+		{list[2].Pets == nil, true},
+		{list[0].Pet.Name, "Kit"},
+		{list[2].Pet == nil, true},
+		{fmt.Sprint(Map(list[0].Toys, "Name")), "[Ball]"},
+		{fmt.Sprint(Map(list[1].Toys, "Name")), "[Bone]"},
+		{list[2].Toys == nil, true},
+		{names(tagged[0].Pets), "[Rex Kit]"},
+		{fmt.Sprint(Map(tagged[1].Toys, "Name")), "[Bone]"},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestJoinAssociationErrors(t *testing.T) {
+	pets := []*Pet{{ID: 1, OwnerID: 1}}
+	_, errList := New(owners()).JoinManyE(1)
+	_, errField := New(owners()).JoinManyGenericE(pets, "Missing", "OwnerID", "ID")
+	_, errType := New(owners()).JoinOneGenericE(pets, "Pets", "OwnerID", "ID")
+	_, errKeys := New(owners()).JoinManyGenericE(pets, "Pets", "OwnerID,Region", "ID")
+	_, errKeyType := New(owners()).JoinManyGenericE(pets, "Pets", "Name", "ID")
+	_, errThrough := New(owners()).JoinThroughGenericE([]*OwnerToy{}, []*Toy{}, "Toys", "OwnerID", "Missing", "ID", "ID")
+
+	tests := []struct{ Has, Want interface{} }{
+		{errors.Is(errList, ErrUnsupportedType), true},
+		{errors.Is(errField, ErrFieldNotFound), true},
+		{errors.Is(errType, ErrUnsupportedType), true},
+		{errors.Is(errKeys, ErrBadSignature), true},
+		{errors.Is(errKeyType, ErrUnsupportedType), true},
+		{errors.Is(errThrough, ErrFieldNotFound), true},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
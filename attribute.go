@@ -0,0 +1,238 @@
+package generics
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Attribute is an attribute key with lookup options. Everywhere an attribute
+// name is accepted, an Attribute can be used instead.
+//
+// Path is a field name or a dotted path through nested structs, pointers,
+// maps with string keys and slice indexes, e.g. "Account.Name" or
+// "Items.0.Price". When a pointer on the path is nil, a map key is missing or
+// an index is out of range the zero value is returned, unless Strict is set,
+// in which case ErrNilPath is returned by the E functions.
+//
+// Each segment of the path is a field name or the name of a method without
+// arguments and with a single return value, e.g. "FullName" for
+// func (u *User) FullName() string. If Tag is set, fields are also matched by
+// the name in that struct tag, so Attribute{Path: "account_id", Tag: "json"}
+// finds the field tagged `json:"account_id"`. A segment matching more than one
+// field or method is reported as ErrAmbiguous.
+type Attribute struct {
+	Path   string
+	Strict bool
+	Tag    string
+}
+
+// step moves one segment along an attribute path. ok is false when the
+// segment could not be resolved on v.
+type step func(v reflect.Value) (res reflect.Value, ok bool)
+
+// getterCache holds the compiled attribute getters by element type and
+// attribute. Attributes may come from user input, so failed lookups are not
+// cached and at most maxCachedGetters getters are kept.
+var (
+	getterCache   sync.Map
+	cachedGetters atomic.Int64
+)
+
+const maxCachedGetters = 4096
+
+type getterKey struct {
+	el reflect.Type
+	a  Attribute
+}
+
+type compiledGetter struct {
+	tp reflect.Type
+	fn func(v reflect.Value) reflect.Value
+}
+
+func attributeGetter(el reflect.Type, a Attribute) (reflect.Type, func(v reflect.Value) reflect.Value, error) {
+	key := getterKey{el: el, a: a}
+	if c, ok := getterCache.Load(key); ok {
+		c := c.(*compiledGetter)
+		return c.tp, c.fn, nil
+	}
+	tp, fn, err := compileAttribute(el, a)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cachedGetters.Load() < maxCachedGetters {
+		if _, loaded := getterCache.LoadOrStore(key, &compiledGetter{tp: tp, fn: fn}); !loaded {
+			cachedGetters.Add(1)
+		}
+	}
+	return tp, fn, nil
+}
+
+// compileAttribute resolves the path of a once. Consecutive fields are merged
+// into a single index path.
+func compileAttribute(el reflect.Type, a Attribute) (reflect.Type, func(v reflect.Value) reflect.Value, error) {
+	var steps []step
+	var index []int // index path of the last step, if it is a field
+	t := el
+	for _, name := range strings.Split(a.Path, ".") {
+		for t.Kind() == reflect.Ptr {
+			steps = append(steps, derefStep)
+			index = nil
+			t = t.Elem()
+		}
+		r, err := pathStep(t, name, a.Tag)
+		if err != nil {
+			return nil, nil, err
+		}
+		if r.index != nil && index != nil {
+			index = append(index[:len(index):len(index)], r.index...)
+			steps[len(steps)-1] = indexStep(index)
+		} else {
+			steps = append(steps, r.step)
+			index = r.index
+		}
+		t = r.tp
+	}
+	return t, func(v reflect.Value) reflect.Value {
+		for _, s := range steps {
+			var ok bool
+			if v, ok = s(v); !ok {
+				if a.Strict {
+					panic(&Error{Kind: ErrNilPath, Type: el, Field: a.Path, Msg: fmt.Sprintf("unable to resolve %s on %v", a.Path, el)})
+				}
+				return reflect.Zero(t)
+			}
+		}
+		return v
+	}, nil
+}
+
+func pathStep(t reflect.Type, name, tag string) (resolved, error) {
+	var found []resolved
+	if t.Kind() == reflect.Struct {
+		if field, ok := t.FieldByName(name); ok {
+			found = append(found, fieldStep(field, "field "+field.Name))
+		}
+		if tag != "" {
+			for _, field := range reflect.VisibleFields(t) {
+				if !field.IsExported() || tagName(field, tag) != name || (len(found) > 0 && field.Name == name) {
+					continue
+				}
+				found = append(found, fieldStep(field, fmt.Sprintf("field %s (%s tag)", field.Name, tag)))
+			}
+		}
+	}
+	if m, ok := zeroArgMethod(t, name); ok {
+		found = append(found, resolved{
+			step: func(v reflect.Value) (reflect.Value, bool) {
+				return callMethod(v, m), true
+			},
+			tp:   m.Type.Out(0),
+			desc: "method " + m.Name,
+		})
+	}
+	switch len(found) {
+	case 0:
+	case 1:
+		return found[0], nil
+	default:
+		descs := make([]string, 0, len(found))
+		for _, r := range found {
+			descs = append(descs, r.desc)
+		}
+		return resolved{}, &Error{Kind: ErrAmbiguous, Type: t, Field: name,
+			Msg: fmt.Sprintf("%s matches %s in %v", name, strings.Join(descs, " and "), t)}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return resolved{}, fieldNotFound(t, name)
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return resolved{}, unsupportedType(t, "expected map with string keys to look up "+name)
+		}
+		key := reflect.ValueOf(name).Convert(t.Key())
+		return resolved{step: func(v reflect.Value) (reflect.Value, bool) {
+			res := v.MapIndex(key)
+			return res, res.IsValid()
+		}, tp: t.Elem()}, nil
+	case reflect.Slice, reflect.Array:
+		idx, err := strconv.Atoi(name)
+		if err != nil || idx < 0 {
+			return resolved{}, fieldNotFound(t, name)
+		}
+		return resolved{step: func(v reflect.Value) (reflect.Value, bool) {
+			if idx >= v.Len() {
+				return v, false
+			}
+			return v.Index(idx), true
+		}, tp: t.Elem()}, nil
+	}
+	return resolved{}, unsupportedType(t, "expected struct, map or slice to look up attribute "+name)
+}
+
+// resolved is a candidate for a path segment. index is set for fields.
+type resolved struct {
+	step  step
+	tp    reflect.Type
+	desc  string
+	index []int
+}
+
+func fieldStep(field reflect.StructField, desc string) resolved {
+	return resolved{step: indexStep(field.Index), tp: field.Type, desc: desc, index: field.Index}
+}
+
+func indexStep(index []int) step {
+	if len(index) == 1 {
+		i := index[0]
+		return func(v reflect.Value) (reflect.Value, bool) {
+			return v.Field(i), true
+		}
+	}
+	return func(v reflect.Value) (reflect.Value, bool) {
+		f, err := v.FieldByIndexErr(index)
+		return f, err == nil
+	}
+}
+
+func tagName(field reflect.StructField, tag string) string {
+	name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+	return name
+}
+
+// zeroArgMethod looks up an exported method without arguments and with one
+// return value on t or *t.
+func zeroArgMethod(t reflect.Type, name string) (reflect.Method, bool) {
+	for _, mt := range []reflect.Type{t, reflect.PtrTo(t)} {
+		if m, ok := mt.MethodByName(name); ok && m.Type.NumIn() == 1 && m.Type.NumOut() == 1 {
+			return m, true
+		}
+	}
+	return reflect.Method{}, false
+}
+
+// callMethod calls m on recv, which is copied into a new value when m has a
+// pointer receiver and recv is not addressable.
+func callMethod(recv reflect.Value, m reflect.Method, args ...reflect.Value) reflect.Value {
+	if m.Type.In(0) != recv.Type() {
+		if !recv.CanAddr() {
+			p := reflect.New(recv.Type())
+			p.Elem().Set(recv)
+			recv = p.Elem()
+		}
+		recv = recv.Addr()
+	}
+	return m.Func.Call(append([]reflect.Value{recv}, args...))[0]
+}
+
+func derefStep(v reflect.Value) (reflect.Value, bool) {
+	if v.IsNil() {
+		return v, false
+	}
+	return v.Elem(), true
+}
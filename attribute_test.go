@@ -0,0 +1,198 @@
+package generics
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type item struct {
+	Price int
+}
+
+type order struct {
+	ID      int
+	Account *Account
+	Items   []item
+	Labels  map[string]string
+}
+
+func orders() []*order {
+	return []*order{
+		{ID: 1, Account: &Account{Name: "b"}, Items: []item{{3}, {1}}, Labels: map[string]string{"color": "red"}},
+		{ID: 2, Account: nil, Items: nil, Labels: nil},
+		{ID: 3, Account: &Account{Name: "a"}, Items: []item{{2}}, Labels: map[string]string{"color": "blue"}},
+	}
+}
+
+func TestAttributePaths(t *testing.T) {
+	tests := []struct {
+		Path string
+		Want string
+	}{
+		{"Account.Name", "[b  a]"},
+		{"Account.ID", "[0 0 0]"},
+		{"Items.0.Price", "[3 0 2]"},
+		{"Items.1.Price", "[1 0 0]"},
+		{"Labels.color", "[red  blue]"},
+		{"Labels.size", "[  ]"},
+	}
+	for _, tc := range tests {
+		res, err := MapE(orders(), tc.Path)
+		if err != nil {
+			t.Errorf("%s: %v", tc.Path, err)
+			continue
+		}
+		if has := fmt.Sprint(res); has != tc.Want {
+			t.Errorf("%s: want %s, was %s", tc.Path, tc.Want, has)
+		}
+	}
+}
+
+func TestAttributePathSortAndGroup(t *testing.T) {
+	list := orders()
+	Sort(list, "Account.Name")
+	if has, want := fmt.Sprint(Map(list, "ID")), "[2 3 1]"; has != want {
+		t.Errorf("want %s, was %s", want, has)
+	}
+
+	groups := Group(list, "Labels.color").(map[string][]*order)
+	if has := len(groups[""]); has != 1 {
+		t.Errorf("expected 1 order without color, was %d", has)
+	}
+}
+
+func TestAttributePathErrors(t *testing.T) {
+	_, strictErr := MapE(orders(), Attribute{Path: "Account.Name", Strict: true})
+	_, missingErr := MapE(orders(), "Account.Missing")
+	_, indexErr := MapE(orders(), "Items.first.Price")
+	_, kindErr := MapE(orders(), "ID.Value")
+
+	tests := []struct {
+		Err  error
+		Want error
+	}{
+		{strictErr, ErrNilPath},
+		{missingErr, ErrFieldNotFound},
+		{indexErr, ErrFieldNotFound},
+		{kindErr, ErrUnsupportedType},
+	}
+	for i, tc := range tests {
+		if !errors.Is(tc.Err, tc.Want) {
+			t.Errorf("%d: want %v, was %v", i+1, tc.Want, tc.Err)
+		}
+	}
+
+	if err := SortE(orders(), Attribute{Path: "Items.0.Price", Strict: true}); !errors.Is(err, ErrNilPath) {
+		t.Errorf("want ErrNilPath from SortE, was %v", err)
+	}
+}
+
+type user struct {
+	First     string `json:"first_name"`
+	Last      string `json:"last_name"`
+	AccountID int    `json:"account_id,omitempty"`
+	Nick      string `json:"First"`
+}
+
+func (u *user) FullName() string {
+	return u.First + " " + u.Last
+}
+
+func (u user) Initials() string {
+	return u.First[:1] + u.Last[:1]
+}
+
+func TestAttributeMethodsAndTags(t *testing.T) {
+	users := []user{
+		{First: "Ada", Last: "Lovelace", AccountID: 2, Nick: "ada"},
+		{First: "Alan", Last: "Turing", AccountID: 1, Nick: "alan"},
+	}
+
+	tests := []struct {
+		Key  interface{}
+		Want string
+	}{
+		{"FullName", "[Ada Lovelace Alan Turing]"},
+		{"Initials", "[AL AT]"},
+		{Attribute{Path: "account_id", Tag: "json"}, "[2 1]"},
+		{Attribute{Path: "last_name", Tag: "json"}, "[Lovelace Turing]"},
+		{Attribute{Path: "Last", Tag: "json"}, "[Lovelace Turing]"},
+	}
+	for i, tc := range tests {
+		res, err := MapE(users, tc.Key)
+		if err != nil {
+			t.Errorf("%d: %v", i+1, err)
+			continue
+		}
+		if has := fmt.Sprint(res); has != tc.Want {
+			t.Errorf("%d: want %s, was %s", i+1, tc.Want, has)
+		}
+	}
+
+	SortStable(users, Attribute{Path: "account_id", Tag: "json"})
+	if has := users[0].First; has != "Alan" {
+		t.Errorf("expected Alan first, was %s", has)
+	}
+}
+
+func TestAttributeAmbiguous(t *testing.T) {
+	_, err := MapE([]user{}, Attribute{Path: "First", Tag: "json"})
+	if !errors.Is(err, ErrAmbiguous) {
+		t.Fatalf("expected ErrAmbiguous, was %v", err)
+	}
+	if has, want := err.Error(), "generics: ambiguous attribute: First matches field First and field Nick (json tag) in generics.user"; has != want {
+		t.Errorf("want %q, was %q", want, has)
+	}
+	if _, err := MapE([]user{}, "First"); err != nil {
+		t.Errorf("expected no error without tag, was %v", err)
+	}
+}
+
+func TestAttributeIndexPaths(t *testing.T) {
+	type inner struct{ X int }
+	type middle struct{ Inner inner }
+	type embedding struct {
+		*inner
+		Middle middle
+	}
+	list := []embedding{
+		{inner: &inner{1}, Middle: middle{inner{2}}},
+		{Middle: middle{inner{3}}},
+	}
+
+	tests := []struct {
+		Path string
+		Want string
+	}{
+		{"X", "[1 0]"},
+		{"Middle.Inner.X", "[2 3]"},
+	}
+	for _, tc := range tests {
+		for i := 0; i < 2; i++ { // the second run uses the cached getter
+			if has := fmt.Sprint(Map(list, tc.Path)); has != tc.Want {
+				t.Errorf("%s: want %s, was %s", tc.Path, tc.Want, has)
+			}
+		}
+	}
+}
+
+func TestAttributeCacheSkipsErrors(t *testing.T) {
+	type unknown struct{ A int }
+	el := reflect.TypeOf(unknown{})
+	for i := 0; i < 2; i++ {
+		if _, _, err := attributeGetter(el, Attribute{Path: "Missing"}); !errors.Is(err, ErrFieldNotFound) {
+			t.Errorf("%d: want ErrFieldNotFound, was %v", i+1, err)
+		}
+	}
+	if _, ok := getterCache.Load(getterKey{el: el, a: Attribute{Path: "Missing"}}); ok {
+		t.Errorf("want failed lookup not to be cached")
+	}
+	if _, _, err := attributeGetter(el, Attribute{Path: "A"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := getterCache.Load(getterKey{el: el, a: Attribute{Path: "A"}}); !ok {
+		t.Errorf("want getter to be cached")
+	}
+}
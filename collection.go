@@ -10,64 +10,133 @@ type Collection struct {
 	collection interface{}
 }
 
+// collectionE wraps the result of an E function, nil on error.
+func collectionE(res interface{}, err error) (*Collection, error) {
+	if err != nil {
+		return nil, err
+	}
+	return New(res), nil
+}
+
 func (c *Collection) FoldLeft(folder interface{}) interface{} {
 	return FoldLeft(c.collection, folder)
 }
 
+func (c *Collection) FoldLeftE(folder interface{}) (interface{}, error) {
+	return FoldLeftE(c.collection, folder)
+}
+
 func (c *Collection) Values(folder interface{}) interface{} {
 	return Values(c.collection)
 }
 
+func (c *Collection) ValuesE() (interface{}, error) {
+	return ValuesE(c.collection)
+}
+
 func (c *Collection) Map(mapper interface{}) *Collection {
 	return New(Map(c.collection, mapper))
 }
 
+func (c *Collection) MapE(mapper interface{}) (*Collection, error) {
+	return collectionE(MapE(c.collection, mapper))
+}
+
 func (c *Collection) Select(fn interface{}) *Collection {
 	return New(Select(c.collection, fn))
 }
 
+func (c *Collection) SelectE(fn interface{}) (*Collection, error) {
+	return collectionE(SelectE(c.collection, fn))
+}
+
 func (c *Collection) Reject(fn interface{}) *Collection {
 	return New(Reject(c.collection, fn))
 }
 
-func (c *Collection) Group(fn interface{}) interface{} {
-	return Group(c.collection, fn)
+func (c *Collection) RejectE(fn interface{}) (*Collection, error) {
+	return collectionE(RejectE(c.collection, fn))
+}
+
+func (c *Collection) Group(keys ...interface{}) interface{} {
+	return Group(c.collection, keys...)
 }
 
-func (c *Collection) Index(fn interface{}) interface{} {
-	return Index(c.collection, fn)
+func (c *Collection) GroupE(keys ...interface{}) (interface{}, error) {
+	return GroupE(c.collection, keys...)
+}
+
+func (c *Collection) Index(keys ...interface{}) interface{} {
+	return Index(c.collection, keys...)
+}
+
+func (c *Collection) IndexE(keys ...interface{}) (interface{}, error) {
+	return IndexE(c.collection, keys...)
 }
 
 func (c *Collection) First() interface{} {
 	return First(c.collection)
 }
 
+func (c *Collection) FirstE() (interface{}, error) {
+	return FirstE(c.collection)
+}
+
 func (c *Collection) Last() interface{} {
 	return Last(c.collection)
 }
 
+func (c *Collection) LastE() (interface{}, error) {
+	return LastE(c.collection)
+}
+
 func (c *Collection) FirstN(n int) *Collection {
 	return New(FirstN(c.collection, n))
 }
 
+func (c *Collection) FirstNE(n int) (*Collection, error) {
+	return collectionE(FirstNE(c.collection, n))
+}
+
 func (c *Collection) LastN(n int) *Collection {
 	return New(LastN(c.collection, n))
 }
 
+func (c *Collection) LastNE(n int) (*Collection, error) {
+	return collectionE(LastNE(c.collection, n))
+}
+
 func (c *Collection) Cast() interface{} {
 	return c.collection
 }
 
-func (c *Collection) Sort(fn interface{}) *Collection {
-	Sort(c.collection, fn)
+func (c *Collection) Sort(keys ...interface{}) *Collection {
+	Sort(c.collection, keys...)
 	return c
 }
 
-func (c *Collection) SortReverse(fn interface{}) *Collection {
-	SortReverse(c.collection, fn)
+func (c *Collection) SortE(keys ...interface{}) (*Collection, error) {
+	return c, SortE(c.collection, keys...)
+}
+
+func (c *Collection) SortReverse(keys ...interface{}) *Collection {
+	SortReverse(c.collection, keys...)
+	return c
+}
+
+func (c *Collection) SortReverseE(keys ...interface{}) (*Collection, error) {
+	return c, SortReverseE(c.collection, keys...)
+}
+
+func (c *Collection) SortStable(keys ...interface{}) *Collection {
+	SortStable(c.collection, keys...)
 	return c
 }
 
+func (c *Collection) SortStableE(keys ...interface{}) (*Collection, error) {
+	return c, SortStableE(c.collection, keys...)
+}
+
 func (c *Collection) Len() int {
 	return reflect.ValueOf(c.collection).Len()
 }
@@ -76,10 +145,134 @@ func (c *Collection) Max() float64 {
 	return Max(c.collection)
 }
 
+func (c *Collection) MaxE(opts ...NumberOption) (float64, error) {
+	return MaxE(c.collection, opts...)
+}
+
 func (c *Collection) Min() float64 {
 	return Min(c.collection)
 }
 
+func (c *Collection) MinE(opts ...NumberOption) (float64, error) {
+	return MinE(c.collection, opts...)
+}
+
 func (c *Collection) Sum() float64 {
 	return Sum(c.collection)
 }
+
+func (c *Collection) SumE(opts ...NumberOption) (float64, error) {
+	return SumE(c.collection, opts...)
+}
+
+func (c *Collection) SumBy(key interface{}) float64 {
+	return SumBy(c.collection, key)
+}
+
+func (c *Collection) SumByE(key interface{}, opts ...NumberOption) (float64, error) {
+	return SumByE(c.collection, key, opts...)
+}
+
+func (c *Collection) AverageBy(key interface{}) float64 {
+	return AverageBy(c.collection, key)
+}
+
+func (c *Collection) AverageByE(key interface{}, opts ...NumberOption) (float64, error) {
+	return AverageByE(c.collection, key, opts...)
+}
+
+func (c *Collection) PercentileBy(key interface{}, perc int) float64 {
+	return PercentileBy(c.collection, key, perc)
+}
+
+func (c *Collection) PercentileByE(key interface{}, perc int, opts ...NumberOption) (float64, error) {
+	return PercentileByE(c.collection, key, perc, opts...)
+}
+
+func (c *Collection) MaxBy(key interface{}) (interface{}, float64) {
+	return MaxBy(c.collection, key)
+}
+
+func (c *Collection) MaxByE(key interface{}, opts ...NumberOption) (interface{}, float64, error) {
+	return MaxByE(c.collection, key, opts...)
+}
+
+func (c *Collection) MinBy(key interface{}) (interface{}, float64) {
+	return MinBy(c.collection, key)
+}
+
+func (c *Collection) MinByE(key interface{}, opts ...NumberOption) (interface{}, float64, error) {
+	return MinByE(c.collection, key, opts...)
+}
+
+func (c *Collection) Pivot(rowKey, colKey interface{}, agg Aggregator, opts ...PivotOption) *PivotTable {
+	return Pivot(c.collection, rowKey, colKey, agg, opts...)
+}
+
+func (c *Collection) PivotE(rowKey, colKey interface{}, agg Aggregator, opts ...PivotOption) (*PivotTable, error) {
+	return PivotE(c.collection, rowKey, colKey, agg, opts...)
+}
+
+func (c *Collection) Rollup(keys []interface{}, agg Aggregator) []RollupRow {
+	return Rollup(c.collection, keys, agg)
+}
+
+func (c *Collection) RollupE(keys []interface{}, agg Aggregator) ([]RollupRow, error) {
+	return RollupE(c.collection, keys, agg)
+}
+
+func (c *Collection) Window(partitionBy interface{}, orderBy ...interface{}) *Window {
+	return NewWindow(c.collection, partitionBy, orderBy...)
+}
+
+func (c *Collection) WindowE(partitionBy interface{}, orderBy ...interface{}) (*Window, error) {
+	return NewWindowE(c.collection, partitionBy, orderBy...)
+}
+
+func (c *Collection) TopN(n int, keys ...interface{}) *Collection {
+	return New(TopN(c.collection, n, keys...))
+}
+
+func (c *Collection) TopNE(n int, keys ...interface{}) (*Collection, error) {
+	return collectionE(TopNE(c.collection, n, keys...))
+}
+
+func (c *Collection) BottomN(n int, keys ...interface{}) *Collection {
+	return New(BottomN(c.collection, n, keys...))
+}
+
+func (c *Collection) BottomNE(n int, keys ...interface{}) (*Collection, error) {
+	return collectionE(BottomNE(c.collection, n, keys...))
+}
+
+func (c *Collection) InnerJoin(right, leftKey, rightKey interface{}) *Collection {
+	return New(InnerJoin(c.collection, right, leftKey, rightKey))
+}
+
+func (c *Collection) InnerJoinE(right, leftKey, rightKey interface{}) (*Collection, error) {
+	return collectionE(InnerJoinE(c.collection, right, leftKey, rightKey))
+}
+
+func (c *Collection) LeftJoin(right, leftKey, rightKey interface{}) *Collection {
+	return New(LeftJoin(c.collection, right, leftKey, rightKey))
+}
+
+func (c *Collection) LeftJoinE(right, leftKey, rightKey interface{}) (*Collection, error) {
+	return collectionE(LeftJoinE(c.collection, right, leftKey, rightKey))
+}
+
+func (c *Collection) RightJoin(right, leftKey, rightKey interface{}) *Collection {
+	return New(RightJoin(c.collection, right, leftKey, rightKey))
+}
+
+func (c *Collection) RightJoinE(right, leftKey, rightKey interface{}) (*Collection, error) {
+	return collectionE(RightJoinE(c.collection, right, leftKey, rightKey))
+}
+
+func (c *Collection) FullOuterJoin(right, leftKey, rightKey interface{}) *Collection {
+	return New(FullOuterJoin(c.collection, right, leftKey, rightKey))
+}
+
+func (c *Collection) FullOuterJoinE(right, leftKey, rightKey interface{}) (*Collection, error) {
+	return collectionE(FullOuterJoinE(c.collection, right, leftKey, rightKey))
+}
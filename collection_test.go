@@ -1,6 +1,10 @@
 package generics
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
 
 func TestCollection(t *testing.T) {
 	type record struct {
@@ -40,3 +44,41 @@ func TestCollection(t *testing.T) {
 		t.Errorf("%d", v)
 	}
 }
+
+func TestCollectionE(t *testing.T) {
+	list := []*record{{Name: "one", Amount: 1}, {Name: "two", Amount: 2}}
+	c := New(list)
+
+	_, errMap := c.MapE("Missing")
+	_, errSelect := c.SelectE(func(r record) bool { return true })
+	_, errGroup := c.GroupE("Missing")
+	_, errGroupBy := c.GroupByE("Missing")
+	_, errSort := c.SortE("Missing")
+	_, errMax := New([]string{"a"}).MaxE()
+	_, errAverage := New([]*record{}).AverageByE("Amount")
+	_, errTop := c.TopNE(1, "Missing")
+	_, errJoin := c.InnerJoinE(list, "Name", "Missing")
+	_, errList := New(1).FirstNE(1)
+	sorted, errNone := c.SortReverseE("Amount")
+	names, _ := sorted.MapE("Name")
+
+	tests := []struct{ Has, Want interface{} }{
+		{errors.Is(errMap, ErrFieldNotFound), true},
+		{errors.Is(errSelect, ErrBadSignature), true},
+		{errors.Is(errGroup, ErrFieldNotFound), true},
+		{errors.Is(errGroupBy, ErrFieldNotFound), true},
+		{errors.Is(errSort, ErrFieldNotFound), true},
+		{errors.Is(errMax, ErrUnsupportedType), true},
+		{errors.Is(errAverage, ErrEmpty), true},
+		{errors.Is(errTop, ErrFieldNotFound), true},
+		{errors.Is(errJoin, ErrFieldNotFound), true},
+		{errors.Is(errList, ErrUnsupportedType), true},
+		{errNone, nil},
+		{fmt.Sprint(names.Cast()), "[two one]"},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package generics
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Errors returned by the E-suffixed functions. Use errors.Is to check for
+// them and errors.As with *Error to get the offending type and field.
+var (
+	ErrFieldNotFound   = errors.New("field not found")
+	ErrUnsupportedType = errors.New("unsupported type")
+	ErrBadSignature    = errors.New("bad signature")
+	ErrIndexOutOfRange = errors.New("index out of range")
+	ErrNilPath         = errors.New("unresolvable attribute path")
+	ErrAmbiguous       = errors.New("ambiguous attribute")
+	ErrPanic           = errors.New("panic in worker")
+	ErrEmpty           = errors.New("empty input")
+	ErrBadTag          = errors.New("invalid association tag")
+)
+
+// Error is returned (or, by the non-E functions, panicked) for all invalid
+// input. Kind is one of the Err* values above.
+type Error struct {
+	Kind  error
+	Type  reflect.Type
+	Field string
+	Msg   string
+}
+
+func (e *Error) Error() string {
+	msg := "generics: " + e.Kind.Error()
+	if e.Msg != "" {
+		msg += ": " + e.Msg
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func fieldNotFound(t reflect.Type, name string) error {
+	return &Error{Kind: ErrFieldNotFound, Type: t, Field: name, Msg: fmt.Sprintf("no attribute with name %s in %v", name, t)}
+}
+
+func unsupportedType(t reflect.Type, msg string) error {
+	return &Error{Kind: ErrUnsupportedType, Type: t, Msg: fmt.Sprintf("%s, was %v", msg, t)}
+}
+
+func badSignature(t reflect.Type, msg string) error {
+	return &Error{Kind: ErrBadSignature, Type: t, Msg: fmt.Sprintf("%s, was %v", msg, t)}
+}
+
+// catch turns a panicked *Error into err. It must be deferred by the E
+// functions calling getters, which report errors by panicking.
+func catch(err *error) {
+	if r := recover(); r != nil {
+		e, ok := r.(*Error)
+		if !ok {
+			panic(r)
+		}
+		*err = e
+	}
+}
+
+func must(res interface{}, err error) interface{} {
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
@@ -0,0 +1,88 @@
+package generics
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	records := []*record{
+		{Name: "one", Amount: 1},
+		{Name: "two", Amount: 2},
+	}
+	type keyed struct {
+		Key []int
+	}
+
+	_, mapErr := MapE(records, "Missing")
+	_, groupErr := GroupE(records, func(r *record, x int) string { return r.Name })
+	_, indexErr := IndexE([]keyed{{}}, "Key")
+	_, foldErr := FoldLeftE(records, func(sum int, r *record) string { return "" })
+	_, selectErr := SelectE(records, func(r *record) int { return 1 })
+	_, rejectErr := RejectE(records, "Name")
+	_, percErr := PercentileE([]int{1, 2}, 101)
+	_, joinErr := New(records).JoinE(map[int]*Account{})
+	_, joinGenericErr := New([]*Payment{}).JoinGenericE([]int{1}, "Account", "AccountID", "ID")
+	_, firstErr := FirstE(1)
+	_, lastErr := LastE(map[int]int{})
+	_, firstNErr := FirstNE("one", 1)
+	_, lastNErr := LastNE(nil, 1)
+	_, keysErr := KeysE(records)
+	_, valuesErr := ValuesE(nil)
+
+	tests := []struct {
+		Name  string
+		Err   error
+		Want  error
+		Field string
+	}{
+		{"MapE", mapErr, ErrFieldNotFound, "Missing"},
+		{"SortE", SortE(records, "Missing"), ErrFieldNotFound, "Missing"},
+		{"SortReverseE", SortReverseE(records, func(r *record) []int { return nil }), ErrUnsupportedType, ""},
+		{"GroupE", groupErr, ErrBadSignature, ""},
+		{"IndexE", indexErr, ErrUnsupportedType, ""},
+		{"FoldLeftE", foldErr, ErrBadSignature, ""},
+		{"SelectE", selectErr, ErrBadSignature, ""},
+		{"RejectE", rejectErr, ErrBadSignature, ""},
+		{"PercentileE", percErr, ErrIndexOutOfRange, ""},
+		{"JoinE", joinErr, ErrFieldNotFound, "AccountID"},
+		{"JoinGenericE", joinGenericErr, ErrUnsupportedType, ""},
+		{"FirstE", firstErr, ErrUnsupportedType, ""},
+		{"LastE", lastErr, ErrUnsupportedType, ""},
+		{"FirstNE", firstNErr, ErrUnsupportedType, ""},
+		{"LastNE", lastNErr, ErrUnsupportedType, ""},
+		{"KeysE", keysErr, ErrUnsupportedType, ""},
+		{"ValuesE", valuesErr, ErrUnsupportedType, ""},
+	}
+	for _, tc := range tests {
+		if !errors.Is(tc.Err, tc.Want) {
+			t.Errorf("%s: want %v, was %v", tc.Name, tc.Want, tc.Err)
+			continue
+		}
+		var e *Error
+		if !errors.As(tc.Err, &e) {
+			t.Errorf("%s: expected *Error, was %T", tc.Name, tc.Err)
+			continue
+		}
+		if e.Field != tc.Field {
+			t.Errorf("%s: want field %q, was %q", tc.Name, tc.Field, e.Field)
+		}
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	err := SortE([]*record{}, "Missing")
+	if has, want := err.Error(), "generics: field not found: no attribute with name Missing in generics.record"; has != want {
+		t.Errorf("want %q, was %q", want, has)
+	}
+}
+
+func TestPanicsWithError(t *testing.T) {
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !errors.Is(err, ErrFieldNotFound) {
+			t.Errorf("expected ErrFieldNotFound, was %v", err)
+		}
+	}()
+	Map([]*record{}, "Missing")
+}
@@ -0,0 +1,144 @@
+package generics
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// SumExact returns the exact sum of in, converted like Numbers. Integers are
+// added as int64 and switch to big.Int when that would overflow, floats and
+// decimal strings are added as big.Rat. Floats are taken by their shortest
+// decimal representation, so 0.1 is added as 1/10 rather than its binary
+// approximation.
+//
+// Use FloatString to format the result with a fixed scale:
+//
+//	sum, err := SumExact(amounts)
+//	fmt.Println(sum.FloatString(2))
+func SumExact(in interface{}, opts ...NumberOption) (*big.Rat, error) {
+	s, err := exactSum(in, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.result(), nil
+}
+
+// AverageExact returns the exact mean of in, see SumExact. It returns
+// ErrEmpty for empty input.
+func AverageExact(in interface{}, opts ...NumberOption) (*big.Rat, error) {
+	s, err := exactSum(in, opts)
+	if err != nil {
+		return nil, err
+	}
+	if s.n == 0 {
+		return nil, &Error{Kind: ErrEmpty, Type: reflect.TypeOf(in), Msg: "no values to compute average of"}
+	}
+	res := s.result()
+	return res.Quo(res, new(big.Rat).SetInt64(int64(s.n))), nil
+}
+
+// accumulator sums integers in an int64 until it overflows and everything
+// else in a big.Rat.
+type accumulator struct {
+	small int64
+	big   *big.Int // set after small overflowed
+	rat   big.Rat
+	n     int
+}
+
+func (a *accumulator) addInt(i int64) {
+	a.n++
+	if a.big != nil {
+		a.big.Add(a.big, big.NewInt(i))
+		return
+	}
+	sum := a.small + i
+	if (i > 0 && sum < a.small) || (i < 0 && sum > a.small) {
+		a.big = big.NewInt(a.small)
+		a.big.Add(a.big, big.NewInt(i))
+		return
+	}
+	a.small = sum
+}
+
+func (a *accumulator) addUint(u uint64) {
+	if u <= math.MaxInt64 {
+		a.addInt(int64(u))
+		return
+	}
+	a.n++
+	if a.big == nil {
+		a.big = big.NewInt(a.small)
+	}
+	a.big.Add(a.big, new(big.Int).SetUint64(u))
+}
+
+func (a *accumulator) addRat(r *big.Rat) {
+	a.n++
+	a.rat.Add(&a.rat, r)
+}
+
+func (a *accumulator) result() *big.Rat {
+	res := new(big.Rat).Set(&a.rat)
+	if a.big != nil {
+		return res.Add(res, new(big.Rat).SetInt(a.big))
+	}
+	return res.Add(res, new(big.Rat).SetInt64(a.small))
+}
+
+func exactSum(in interface{}, opts []NumberOption) (*accumulator, error) {
+	o := newNumberOptions(opts)
+	v, err := sliceValue(in)
+	if err != nil {
+		return nil, err
+	}
+	acc := &accumulator{}
+	for i := 0; i < v.Len(); i++ {
+		if err := acc.add(v.Index(i), o); err != nil {
+			return nil, elementError(err, i)
+		}
+	}
+	return acc, nil
+}
+
+// add adds v, converted like toFloat does.
+func (a *accumulator) add(v reflect.Value, o *numberOptions) error {
+	v, ok, err := deref(v, o)
+	if !ok {
+		return err
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		a.addInt(v.Int())
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		a.addUint(v.Uint())
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return &Error{Kind: ErrUnsupportedType, Type: v.Type(), Msg: fmt.Sprintf("%v is not a finite number", f)}
+		}
+		bits := 64
+		if v.Kind() == reflect.Float32 {
+			bits = 32
+		}
+		r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, bits))
+		a.addRat(r)
+		return nil
+	case reflect.String:
+		if v.Type() != jsonNumberType && !o.parseStrings {
+			break
+		}
+		r, ok := new(big.Rat).SetString(v.String())
+		if !ok {
+			return &Error{Kind: ErrUnsupportedType, Type: v.Type(), Msg: fmt.Sprintf("%q is not a number", v.String())}
+		}
+		a.addRat(r)
+		return nil
+	}
+	return unsupportedType(v.Type(), "expected number")
+}
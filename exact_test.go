@@ -0,0 +1,54 @@
+package generics
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSumExact(t *testing.T) {
+	tests := []struct {
+		In    interface{}
+		Opts  []NumberOption
+		Scale int
+		Want  string
+	}{
+		{[]float64{0.1, 0.2}, nil, 2, "0.30"},
+		{[]float64{0.1, 0.2}, nil, 20, "0.30000000000000000000"},
+		{[]float32{0.1, 0.2}, nil, 20, "0.30000000000000000000"},
+		{[]int64{math.MaxInt64, math.MaxInt64, -1}, nil, 0, "18446744073709551613"},
+		{[]int64{math.MinInt64, -1}, nil, 0, "-9223372036854775809"},
+		{[]uint64{math.MaxUint64, 1}, nil, 0, "18446744073709551616"},
+		{[]interface{}{1, 0.5, json.Number("0.25")}, nil, 3, "1.750"},
+		{[]string{"19.99", "0.01"}, []NumberOption{ParseStrings()}, 2, "20.00"},
+		{[]string{"1.005"}, []NumberOption{ParseStrings()}, 2, "1.01"},
+		{[]int{}, nil, 1, "0.0"},
+	}
+	for i, tc := range tests {
+		sum, err := SumExact(tc.In, tc.Opts...)
+		if err != nil {
+			t.Errorf("%d: %v", i+1, err)
+			continue
+		}
+		if has := sum.FloatString(tc.Scale); has != tc.Want {
+			t.Errorf("%d: want %s, was %s", i+1, tc.Want, has)
+		}
+	}
+}
+
+func TestAverageExact(t *testing.T) {
+	avg, err := AverageExact([]float64{0.1, 0.2, 0.3})
+	if err != nil || avg.FloatString(20) != "0.20000000000000000000" {
+		t.Errorf("was %v, %v", avg, err)
+	}
+	if _, err := AverageExact([]int{}); !errors.Is(err, ErrEmpty) {
+		t.Errorf("expected ErrEmpty, was %v", err)
+	}
+	if _, err := SumExact([]float64{math.Inf(1)}); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, was %v", err)
+	}
+	if _, err := SumExact([]string{"1"}); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, was %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package generics
+
+// Fast paths for the most common slice and func types, which skip
+// reflection altogether.
+
+func mapFast(i interface{}, fn interface{}) (interface{}, bool) {
+	switch list := i.(type) {
+	case []int:
+		return mapFastFrom(list, fn)
+	case []string:
+		return mapFastFrom(list, fn)
+	case []float64:
+		return mapFastFrom(list, fn)
+	}
+	return nil, false
+}
+
+func mapFastFrom[T any](list []T, fn interface{}) (interface{}, bool) {
+	switch fn := fn.(type) {
+	case func(T) int:
+		return mapTyped(list, fn), true
+	case func(T) string:
+		return mapTyped(list, fn), true
+	case func(T) float64:
+		return mapTyped(list, fn), true
+	}
+	return nil, false
+}
+
+func mapTyped[T, U any](list []T, fn func(T) U) []U {
+	out := make([]U, len(list))
+	for i, v := range list {
+		out[i] = fn(v)
+	}
+	return out
+}
+
+func selectFast(i interface{}, filter interface{}) (interface{}, bool) {
+	switch list := i.(type) {
+	case []int:
+		return selectFastFrom(list, filter)
+	case []string:
+		return selectFastFrom(list, filter)
+	case []float64:
+		return selectFastFrom(list, filter)
+	}
+	return nil, false
+}
+
+func selectFastFrom[T any](list []T, filter interface{}) (interface{}, bool) {
+	fn, ok := filter.(func(T) bool)
+	if !ok {
+		return nil, false
+	}
+	out := make([]T, 0, len(list))
+	for _, v := range list {
+		if fn(v) {
+			out = append(out, v)
+		}
+	}
+	return out, true
+}
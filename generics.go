@@ -2,123 +2,114 @@ package generics
 
 import (
 	"fmt"
-	"math"
 	"reflect"
-	"sort"
-	"time"
 )
 
-func Sort(list interface{}, fn interface{}) {
-	s := sorter(list, fn)
-	sort.Slice(list, s)
-}
-
-func SortReverse(list interface{}, fn interface{}) {
-	s := sorter(list, fn)
-	r := func(a, b int) bool {
-		return !s(a, b)
-	}
-	sort.Slice(list, r)
-}
-
-func sorter(list interface{}, fn interface{}) func(a, b int) bool {
-	v := reflect.ValueOf(list)
-	//fnv := reflect.ValueOf(fn)
-
-	etp := reflect.TypeOf(list).Elem()
-	if etp.Kind() == reflect.Ptr {
-		etp = etp.Elem()
-	}
-	_, getter := newGetter(etp, fn)
-	_ = getter
-	return func(a, b int) bool {
-		vA := getter(v.Index(a))
-		vB := getter(v.Index(b))
-		switch as := vA.Interface().(type) {
-		case string:
-			bs := vB.Interface().(string)
-			return as < bs
-		case int:
-			bs := vB.Interface().(int)
-			return as < bs
-		case float64:
-			bs := vB.Interface().(float64)
-			return as < bs
-		case time.Time:
-			bs := vB.Interface().(time.Time)
-			return as.Before(bs)
-		default:
-			panic("type " + fmt.Sprintf("%T", as) + " not supported")
-		}
-		return false
-	}
+func Map(i interface{}, fn interface{}) interface{} {
+	return must(MapE(i, fn))
 }
 
-func Map(i interface{}, fn interface{}) interface{} {
-	v := reflect.ValueOf(i)
-	t := reflect.TypeOf(i)
-	el := t.Elem()
-	if el.Kind() == reflect.Ptr {
-		el = el.Elem()
+func MapE(i interface{}, fn interface{}) (res interface{}, err error) {
+	defer catch(&err)
+	v, err := sliceValue(i)
+	if err != nil {
+		return nil, err
 	}
 
-	tp, getter := newGetter(el, fn)
-	if getter == nil {
-		panic("getter is null")
+	if res, ok := mapFast(i, fn); ok {
+		return res, nil
 	}
-	out := reflect.New(reflect.SliceOf(tp))
 
+	tp, getter, err := newGetter(v.Type().Elem(), fn)
+	if err != nil {
+		return nil, err
+	}
+	out := reflect.MakeSlice(reflect.SliceOf(tp), v.Len(), v.Len())
 	for i := 0; i < v.Len(); i++ {
-		el := v.Index(i)
-		fv := getter(el)
-		out.Elem().Set(reflect.Append(out.Elem(), fv))
+		out.Index(i).Set(getter(v.Index(i)))
 	}
-	return out.Elem().Interface()
+	return out.Interface(), nil
 }
 
 func Attributes(i interface{}, name string) interface{} {
 	return Map(i, name)
 }
 
+func AttributesE(i interface{}, name string) (interface{}, error) {
+	return MapE(i, name)
+}
+
 func Values(i interface{}) interface{} {
-	t := reflect.TypeOf(i)
-	v := reflect.ValueOf(i)
-	out := reflect.New(reflect.SliceOf(t.Elem()))
-	for _, k := range v.MapKeys() {
-		el := v.MapIndex(k)
-		n := reflect.Append(out.Elem(), el)
-		out.Elem().Set(n)
+	return must(ValuesE(i))
+}
+
+func ValuesE(i interface{}) (interface{}, error) {
+	v, err := mapValue(i)
+	if err != nil {
+		return nil, err
+	}
+	out := reflect.MakeSlice(reflect.SliceOf(v.Type().Elem()), v.Len(), v.Len())
+	for idx, it := 0, v.MapRange(); it.Next(); idx++ {
+		out.Index(idx).SetIterValue(it)
 	}
-	return out.Elem().Interface()
+	return out.Interface(), nil
 }
 
 func Keys(i interface{}) interface{} {
-	t := reflect.TypeOf(i)
+	return must(KeysE(i))
+}
+
+func KeysE(i interface{}) (interface{}, error) {
+	v, err := mapValue(i)
+	if err != nil {
+		return nil, err
+	}
+	out := reflect.MakeSlice(reflect.SliceOf(v.Type().Key()), v.Len(), v.Len())
+	for idx, it := 0, v.MapRange(); it.Next(); idx++ {
+		out.Index(idx).SetIterKey(it)
+	}
+	return out.Interface(), nil
+}
+
+func mapValue(i interface{}) (reflect.Value, error) {
 	v := reflect.ValueOf(i)
-	out := reflect.New(reflect.SliceOf(t.Key()))
-	for _, k := range v.MapKeys() {
-		n := reflect.Append(out.Elem(), k)
-		out.Elem().Set(n)
+	if v.Kind() != reflect.Map {
+		return v, unsupportedType(reflect.TypeOf(i), "expected map")
 	}
-	return out.Elem().Interface()
+	return v, nil
 }
 
 // FoldLeft folds ... left
-// FoldLeft([]int{1, 2, 3}, func(list []int{}, value int) []int {
-//   return append(list, value * value)
-// }).([]int) => [1, 4, 6]
+//
+//	FoldLeft([]int{1, 2, 3}, func(list []int{}, value int) []int {
+//	  return append(list, value * value)
+//	}).([]int) => [1, 4, 6]
 func FoldLeft(col interface{}, folder interface{}) interface{} {
+	return must(FoldLeftE(col, folder))
+}
+
+func FoldLeftE(col interface{}, folder interface{}) (interface{}, error) {
+	v, err := sliceValue(col)
+	if err != nil {
+		return nil, err
+	}
 	ft := reflect.TypeOf(folder)
+	if ft == nil || ft.Kind() != reflect.Func {
+		return nil, badSignature(ft, "folder must be a func")
+	}
 	if ft.NumIn() != 2 {
-		panic("folder must have 2 input parameters")
+		return nil, badSignature(ft, "folder must have 2 input parameters")
 	}
 	if ft.NumOut() != 1 {
-		panic("folder must have 1 return value")
+		return nil, badSignature(ft, "folder must have 1 return value")
 	}
 	accType := ft.In(0)
 	outType := ft.Out(0)
 	if accType != outType {
-		panic(fmt.Sprintf("acc type %v must be the same as out type %v", accType, outType))
+		return nil, badSignature(ft, fmt.Sprintf("acc type %v must be the same as out type %v", accType, outType))
+	}
+	if !v.Type().Elem().AssignableTo(ft.In(1)) {
+		return nil, badSignature(ft, fmt.Sprintf("folder must accept %v as second parameter", v.Type().Elem()))
 	}
 	if accType.Kind() == reflect.Ptr {
 		accType = accType.Elem()
@@ -134,33 +125,44 @@ func FoldLeft(col interface{}, folder interface{}) interface{} {
 	default:
 		ret = reflect.New(accType).Elem()
 	}
-	v := reflect.ValueOf(col)
 	folderValue := reflect.ValueOf(folder)
 	for i := 0; i < v.Len(); i++ {
 		el := v.Index(i)
-		res := folderValue.Call([]reflect.Value{ret, el})
-		if len(res) != 1 {
-			panic("folder must return 1 result")
-		}
-		ret = res[0]
+		ret = folderValue.Call([]reflect.Value{ret, el})[0]
 	}
-	return ret.Interface()
+	return ret.Interface(), nil
 }
 
-func Group(i interface{}, fn interface{}) interface{} {
-	el := reflect.ValueOf(i).Type().Elem()
+// Group groups the elements of i by one or more keys, attributes or funcs as
+// accepted by Map, into a map[K][]T. Several keys are combined into a struct,
+// see CompositeKey:
+//
+//	Group(payments, "AccountID", "Currency").(map[struct {
+//		AccountID int
+//		Currency  string
+//	}][]*Payment)
+//
+// Use GroupNested for nested maps instead.
+func Group(i interface{}, keys ...interface{}) interface{} {
+	return must(GroupE(i, keys...))
+}
 
-	sel := el
-	if el.Kind() == reflect.Ptr {
-		sel = sel.Elem()
+func GroupE(i interface{}, keys ...interface{}) (res interface{}, err error) {
+	defer catch(&err)
+	v, err := sliceValue(i)
+	if err != nil {
+		return nil, err
 	}
+	el := v.Type().Elem()
 
-	tp, getter := newGetter(sel, fn)
+	tp, getter, err := compositeGetter(el, keys)
+	if err != nil {
+		return nil, err
+	}
 
 	st := reflect.SliceOf(el)
 	m := reflect.MakeMap(reflect.MapOf(tp, st))
 
-	v := reflect.ValueOf(i)
 	for i := 0; i < v.Len(); i++ {
 		el := v.Index(i)
 		v := getter(el)
@@ -173,88 +175,125 @@ func Group(i interface{}, fn interface{}) interface{} {
 		n := reflect.Append(sl, el)
 		m.SetMapIndex(v, n)
 	}
-	return m.Interface()
+	return m.Interface(), nil
 }
 
-func Index(i interface{}, fn interface{}) interface{} {
-	el := reflect.ValueOf(i).Type().Elem()
-	if el.Kind() == reflect.Slice {
-		el = el.Elem()
-	}
-	sel := el
-	if el.Kind() == reflect.Ptr {
-		sel = sel.Elem()
+// Index maps the elements of i by one or more keys into a map[K]T, see Group.
+func Index(i interface{}, keys ...interface{}) interface{} {
+	return must(IndexE(i, keys...))
+}
+
+func IndexE(i interface{}, keys ...interface{}) (res interface{}, err error) {
+	defer catch(&err)
+	v, err := sliceValue(i)
+	if err != nil {
+		return nil, err
 	}
+	el := v.Type().Elem()
 
-	tp, getter := newGetter(sel, fn)
+	tp, getter, err := compositeGetter(el, keys)
+	if err != nil {
+		return nil, err
+	}
 
 	m := reflect.MakeMap(reflect.MapOf(tp, el))
 
-	v := reflect.ValueOf(i)
-	if v.Kind() == reflect.Ptr {
-		v = v.Elem()
-	}
 	for i := 0; i < v.Len(); i++ {
 		el := v.Index(i)
 		v := getter(el)
 		m.SetMapIndex(v, el)
 	}
-	return m.Interface()
+	return m.Interface(), nil
 }
 
 func Reject(i interface{}, rejecter interface{}) interface{} {
-	return Select(i, negate(rejecter))
+	return must(RejectE(i, rejecter))
+}
+
+func RejectE(i interface{}, rejecter interface{}) (interface{}, error) {
+	v, err := sliceValue(i)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := filterFunc(v.Type().Elem(), rejecter); err != nil {
+		return nil, err
+	}
+	return SelectE(i, negate(rejecter))
 }
 
 func Filter(i interface{}, filter interface{}) interface{} {
 	return Select(i, filter)
 }
 
+func FilterE(i interface{}, filter interface{}) (interface{}, error) {
+	return SelectE(i, filter)
+}
+
 func Select(i interface{}, filter interface{}) interface{} {
-	fun := reflect.ValueOf(filter)
-	v := reflect.ValueOf(i)
-	out := reflect.New(reflect.TypeOf(i))
-	for i := 0; i < v.Len(); i++ {
-		el := v.Index(i)
-		res := fun.Call([]reflect.Value{el})
-		if len(res) != 1 {
-			panic("must return bool")
+	return must(SelectE(i, filter))
+}
+
+func SelectE(i interface{}, filter interface{}) (interface{}, error) {
+	v, err := sliceValue(i)
+	if err != nil {
+		return nil, err
+	}
+	fun, err := filterFunc(v.Type().Elem(), filter)
+	if err != nil {
+		return nil, err
+	}
+	if res, ok := selectFast(i, filter); ok {
+		return res, nil
+	}
+
+	// filter first so the result can be allocated with its final length
+	keep := make([]bool, v.Len())
+	n := 0
+	for i := range keep {
+		if keep[i] = fun.Call([]reflect.Value{v.Index(i)})[0].Bool(); keep[i] {
+			n++
 		}
-		v := res[0]
-		if v.Bool() {
-			n := reflect.Append(out.Elem(), el)
-			out.Elem().Set(n)
+	}
+	out := reflect.MakeSlice(resultSliceType(v), n, n)
+	idx := 0
+	for i, k := range keep {
+		if k {
+			out.Index(idx).Set(v.Index(i))
+			idx++
 		}
 	}
-	return out.Elem().Interface()
+	return out.Interface(), nil
 }
 
-func newGetter(el reflect.Type, i interface{}) (sliceType reflect.Type, fn func(v reflect.Value) reflect.Value) {
+func filterFunc(el reflect.Type, filter interface{}) (reflect.Value, error) {
+	fun := reflect.ValueOf(filter)
+	ft := reflect.TypeOf(filter)
+	if ft == nil || ft.Kind() != reflect.Func || ft.NumIn() != 1 || ft.NumOut() != 1 ||
+		ft.Out(0).Kind() != reflect.Bool || !el.AssignableTo(ft.In(0)) {
+		return fun, badSignature(ft, fmt.Sprintf("expected func(%v) bool", el))
+	}
+	return fun, nil
+}
+
+func newGetter(el reflect.Type, i interface{}) (sliceType reflect.Type, fn func(v reflect.Value) reflect.Value, err error) {
+	if a, ok := i.(Attribute); ok {
+		return attributeGetter(el, a)
+	}
 	fnv := reflect.ValueOf(i)
 	switch fnv.Kind() {
 	case reflect.String:
-		name := fnv.String()
-		field, ok := el.FieldByName(name)
-		if !ok {
-			panic("no attribute with name " + name)
-		}
-		return field.Type, func(v reflect.Value) reflect.Value {
-			if v.Kind() == reflect.Ptr {
-				v = v.Elem()
-			}
-			return v.FieldByName(name)
-		}
+		return attributeGetter(el, Attribute{Path: fnv.String()})
 	case reflect.Func:
-		tp := fnv.Type().Out(0)
-		return tp, func(v reflect.Value) reflect.Value {
-			res := fnv.Call([]reflect.Value{v})
-			if len(res) != 1 {
-				panic("must return one argument")
-			}
-			return res[0]
+		ft := fnv.Type()
+		if ft.NumIn() != 1 || ft.NumOut() != 1 || !el.AssignableTo(ft.In(0)) {
+			return nil, nil, badSignature(ft, fmt.Sprintf("expected func(%v) with one return value", el))
 		}
+		tp := ft.Out(0)
+		return tp, func(v reflect.Value) reflect.Value {
+			return fnv.Call([]reflect.Value{v})[0]
+		}, nil
 	default:
-		panic("kind " + fnv.Kind().String() + " not supported")
+		return nil, nil, unsupportedType(reflect.TypeOf(i), "expected attribute name or func")
 	}
 }
 
@@ -262,125 +301,117 @@ func negate(fn interface{}) interface{} {
 	return func(i interface{}) bool {
 		v := reflect.ValueOf(fn)
 		res := v.Call([]reflect.Value{reflect.ValueOf(i)})
-		if len(res) != 1 {
-			panic("must return bool")
-		}
 		return !res[0].Bool()
 	}
 }
 
-func Last(i interface{}) interface{} {
-	v := reflect.ValueOf(i)
-	if v.Len() == 0 {
-		return nullType(v)
+// resultSliceType returns the type of a slice of v's elements: v's own type
+// for slices, so named slice types are kept, and []T for arrays.
+func resultSliceType(v reflect.Value) reflect.Type {
+	if v.Kind() == reflect.Array {
+		return reflect.SliceOf(v.Type().Elem())
 	}
-	return v.Index(v.Len() - 1).Interface()
+	return v.Type()
 }
 
-func First(i interface{}) interface{} {
+// sliceValue returns the reflect.Value of a slice, array or pointer to one.
+func sliceValue(i interface{}) (reflect.Value, error) {
 	v := reflect.ValueOf(i)
-	if v.Len() == 0 {
-		return nullType(v)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		return v, nil
+	default:
+		return v, unsupportedType(reflect.TypeOf(i), "expected slice")
 	}
-	return v.Index(0).Interface()
 }
 
-func FirstN(i interface{}, n int) interface{} {
-	t := reflect.TypeOf(i)
-	v := reflect.ValueOf(i)
-	l := v.Len()
-	if n > l {
-		n = l
-	}
+func Last(i interface{}) interface{} {
+	return must(LastE(i))
+}
 
-	out := reflect.New(t)
-	for idx := 0; idx < n; idx++ {
-		el := v.Index(idx)
-		n := reflect.Append(out.Elem(), el)
-		out.Elem().Set(n)
+func LastE(i interface{}) (interface{}, error) {
+	v, err := sliceValue(i)
+	if err != nil {
+		return nil, err
+	}
+	if v.Len() == 0 {
+		return nullType(v.Type().Elem()), nil
 	}
+	return v.Index(v.Len() - 1).Interface(), nil
+}
 
-	return out.Elem().Interface()
+func First(i interface{}) interface{} {
+	return must(FirstE(i))
 }
 
-func LastN(i interface{}, n int) interface{} {
-	t := reflect.TypeOf(i)
-	v := reflect.ValueOf(i)
-	l := v.Len()
-	if n > l {
-		n = l
+func FirstE(i interface{}) (interface{}, error) {
+	v, err := sliceValue(i)
+	if err != nil {
+		return nil, err
 	}
-	out := reflect.New(t)
-	for i := 0; i < n; i++ {
-		idx := i + (l - n)
-		el := v.Index(idx)
-		n := reflect.Append(out.Elem(), el)
-		out.Elem().Set(n)
+	if v.Len() == 0 {
+		return nullType(v.Type().Elem()), nil
 	}
-
-	return out.Elem().Interface()
+	return v.Index(0).Interface(), nil
 }
 
-func nullType(v reflect.Value) interface{} {
-	et := v.Type().Elem()
-	if et.Kind() == reflect.Ptr {
-		return nil
-	}
-	return reflect.New(et).Elem().Interface()
+// FirstN returns a copy of the first n elements of i.
+func FirstN(i interface{}, n int) interface{} {
+	return must(FirstNE(i, n))
 }
 
-func Max(in interface{}) (max float64) {
-	for _, v := range toFloats(in) {
-		if v > max {
-			max = v
-		}
+func FirstNE(i interface{}, n int) (interface{}, error) {
+	v, err := sliceValue(i)
+	if err != nil {
+		return nil, err
 	}
-	return max
+	return copyRange(v, 0, clamp(n, v.Len())), nil
 }
 
-func Min(in interface{}) (min float64) {
-	for _, v := range toFloats(in) {
-		if v < min {
-			min = v
-		}
-	}
-	return min
+// LastN returns a copy of the last n elements of i.
+func LastN(i interface{}, n int) interface{} {
+	return must(LastNE(i, n))
 }
 
-func Sum(in interface{}) (sum float64) {
-	for _, v := range toFloats(in) {
-		sum += v
+func LastNE(i interface{}, n int) (interface{}, error) {
+	v, err := sliceValue(i)
+	if err != nil {
+		return nil, err
 	}
-	return sum
+	l := v.Len()
+	return copyRange(v, l-clamp(n, l), l), nil
 }
 
-func Average(in interface{}) (res float64) {
-	list := toFloats(in)
-	return Sum(list) / float64(len(list))
+// copyRange copies the elements from to to of v into a new slice, see
+// resultSliceType.
+func copyRange(v reflect.Value, from, to int) interface{} {
+	out := reflect.MakeSlice(resultSliceType(v), to-from, to-from)
+	if v.Kind() == reflect.Slice || v.CanAddr() {
+		reflect.Copy(out, v.Slice(from, to))
+		return out.Interface()
+	}
+	for i := from; i < to; i++ {
+		out.Index(i - from).Set(v.Index(i))
+	}
+	return out.Interface()
 }
 
-func Percentile(i interface{}, perc int) float64 {
-	values := toFloats(i)
-	middle := float64(len(values)) * float64(perc) / 100.0
-	floor := int(math.Floor(middle))
-	if len(values) <= floor {
-		panic(fmt.Sprintf("unabel to get idx %d of %v", floor, values))
+func clamp(n, l int) int {
+	if n > l {
+		return l
 	}
-	return values[floor]
+	if n < 0 {
+		return 0
+	}
+	return n
 }
 
-func toFloats(in interface{}) (out []float64) {
-	v := reflect.ValueOf(in)
-	if v.Kind() == reflect.Ptr {
-		v = v.Elem()
-	}
-	for i := 0; i < v.Len(); i++ {
-		switch c := v.Index(i).Interface().(type) {
-		case float64:
-			out = append(out, c)
-		case int:
-			out = append(out, float64(c))
-		}
+func nullType(et reflect.Type) interface{} {
+	if et.Kind() == reflect.Ptr {
+		return nil
 	}
-	return out
+	return reflect.New(et).Elem().Interface()
 }
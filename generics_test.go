@@ -2,7 +2,9 @@ package generics
 
 import (
 	"fmt"
+	"math/rand"
 	"sort"
+	"strconv"
 	"testing"
 )
 
@@ -296,6 +298,25 @@ func TestSelect(t *testing.T) {
 	}
 }
 
+func TestSelectKeepsSliceType(t *testing.T) {
+	type recordList []*record
+	records := recordList{{Amount: 1}, {Amount: 2}, {Amount: 3}}
+	array := [3]*record{records[0], records[1], records[2]}
+	filter := func(r *record) bool { return r.Amount >= 2 }
+
+	tests := []struct{ Has, Want interface{} }{
+		{len(Select(records, filter).(recordList)), 2},
+		{len(Reject(records, filter).(recordList)), 1},
+		{len(Filter(&records, filter).(recordList)), 2},
+		{len(Select(array, filter).([]*record)), 2},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
 func TestTail(t *testing.T) {
 	in := []string{"one", "two", "three"}
 	records := []*record{{Name: "foo"}}
@@ -365,6 +386,9 @@ func TestHead(t *testing.T) {
 		{FirstN(in, 5).([]string), "[one two three]"},
 		{FirstN([]string{}, 5).([]string), "[]"},
 		{FirstN([]string{}, -1).([]string), "[]"},
+		{FirstN([3]string{"one", "two", "three"}, 2).([]string), "[one two]"},
+		{LastN(&[3]string{"one", "two", "three"}, 2).([]string), "[two three]"},
+		{First(&in), "one"},
 	}
 	for i, tc := range tests {
 		has := fmt.Sprintf("%v", tc.Input)
@@ -373,3 +397,113 @@ func TestHead(t *testing.T) {
 		}
 	}
 }
+
+type benchRecord struct {
+	ID        int64
+	AccountID int
+	Name      string
+	Amount    float64
+}
+
+func benchRecords(n int) []*benchRecord {
+	rnd := rand.New(rand.NewSource(1))
+	list := make([]*benchRecord, n)
+	for i := range list {
+		list[i] = &benchRecord{
+			ID:        rnd.Int63(),
+			AccountID: rnd.Intn(100),
+			Name:      strconv.Itoa(rnd.Int()),
+			Amount:    rnd.Float64(),
+		}
+	}
+	return list
+}
+
+func benchmarkSort(b *testing.B, sortFn func(list []*benchRecord)) {
+	list := benchRecords(100000)
+	work := make([]*benchRecord, len(list))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(work, list)
+		sortFn(work)
+	}
+}
+
+func BenchmarkSortHandWritten(b *testing.B) {
+	benchmarkSort(b, func(list []*benchRecord) {
+		sort.Slice(list, func(a, b int) bool { return list[a].ID < list[b].ID })
+	})
+}
+
+func BenchmarkSortAttribute(b *testing.B) {
+	benchmarkSort(b, func(list []*benchRecord) { Sort(list, "ID") })
+}
+
+func BenchmarkSortFunc(b *testing.B) {
+	benchmarkSort(b, func(list []*benchRecord) {
+		Sort(list, func(r *benchRecord) int64 { return r.ID })
+	})
+}
+
+func BenchmarkSortMultipleKeys(b *testing.B) {
+	benchmarkSort(b, func(list []*benchRecord) { Sort(list, "AccountID", "-Amount", "Name") })
+}
+
+func BenchmarkMap(b *testing.B) {
+	list := benchRecords(10000)
+	ints := Map(list, "AccountID").([]int)
+	b.Run("attribute", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Map(list, "Name")
+		}
+	})
+	b.Run("func", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Map(list, func(r *benchRecord) string { return r.Name })
+		}
+	})
+	b.Run("ints", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Map(ints, func(v int) int { return v * 2 })
+		}
+	})
+}
+
+func BenchmarkSelect(b *testing.B) {
+	list := benchRecords(10000)
+	ints := Map(list, "AccountID").([]int)
+	b.Run("func", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Select(list, func(r *benchRecord) bool { return r.AccountID < 50 })
+		}
+	})
+	b.Run("ints", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Select(ints, func(v int) bool { return v < 50 })
+		}
+	})
+}
+
+func BenchmarkFirstNLastN(b *testing.B) {
+	list := benchRecords(10000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		FirstN(list, 5000)
+		LastN(list, 5000)
+	}
+}
+
+func BenchmarkKeysValues(b *testing.B) {
+	m := Index(benchRecords(10000), "ID")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Keys(m)
+		Values(m)
+	}
+}
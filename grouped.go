@@ -0,0 +1,313 @@
+package generics
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Pair is a group key with its group or an aggregate of it.
+type Pair struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// Pairs are returned by GroupedCollection, ordered by key unless sorted
+// otherwise.
+type Pairs []Pair
+
+// SortByKey sorts the pairs by key in place. Composite keys are compared
+// field by field. It panics with ErrUnsupportedType if the keys cannot be
+// ordered or are of different types.
+func (p Pairs) SortByKey() Pairs {
+	return p.sortBy(func(pair Pair) interface{} { return pair.Key })
+}
+
+// SortByValue sorts the pairs by value in place, e.g. to order groups by an
+// aggregate.
+func (p Pairs) SortByValue() Pairs {
+	return p.sortBy(func(pair Pair) interface{} { return pair.Value })
+}
+
+// Reverse reverses the order of the pairs in place.
+func (p Pairs) Reverse() Pairs {
+	for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+		p[i], p[j] = p[j], p[i]
+	}
+	return p
+}
+
+func (p Pairs) sortBy(fn func(Pair) interface{}) Pairs {
+	values := make([]reflect.Value, len(p))
+	var tp reflect.Type
+	for i, pair := range p {
+		values[i] = reflect.ValueOf(fn(pair))
+		switch {
+		case !values[i].IsValid():
+			// untyped nil sorts like a nil pointer
+		case tp == nil:
+			tp = values[i].Type()
+		case values[i].Type() != tp:
+			panic(unsupportedType(values[i].Type(), fmt.Sprintf("cannot compare %v with different type", tp)))
+		}
+	}
+	if tp == nil {
+		return p
+	}
+	cmp, err := keyCompareFunc(tp)
+	if err != nil {
+		panic(err)
+	}
+	sort.Stable(pairSorter{p, values, &compiledSortKey{cmp: cmp}})
+	return p
+}
+
+type pairSorter struct {
+	p      Pairs
+	values []reflect.Value
+	key    *compiledSortKey
+}
+
+func (s pairSorter) Len() int { return len(s.p) }
+
+func (s pairSorter) Less(a, b int) bool { return s.key.compare(s.values[a], s.values[b]) < 0 }
+
+func (s pairSorter) Swap(a, b int) {
+	s.p[a], s.p[b] = s.p[b], s.p[a]
+	s.values[a], s.values[b] = s.values[b], s.values[a]
+}
+
+// keyCompareFunc returns a comparison of keys of type t like compareFunc.
+// Structs without a comparison method, such as composite keys, are compared
+// field by field.
+func keyCompareFunc(t reflect.Type) (func(a, b reflect.Value) int, error) {
+	if t.Kind() != reflect.Struct || !plainKind(t) {
+		return compareFunc(t)
+	}
+	fields := make([]*compiledSortKey, t.NumField())
+	for i := range fields {
+		f := t.Field(i)
+		if !f.IsExported() {
+			return nil, unsupportedType(t, fmt.Sprintf("cannot compare unexported field %s", f.Name))
+		}
+		cmp, err := keyCompareFunc(f.Type)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = &compiledSortKey{cmp: cmp}
+	}
+	return func(a, b reflect.Value) int {
+		for i, f := range fields {
+			if c := f.compare(a.Field(i), b.Field(i)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}, nil
+}
+
+// GroupedCollection is a collection split into groups by a key, see
+// Collection.GroupBy. Every aggregation returns one Pair per group:
+//
+//	New(payments).GroupBy("AccountID").SumBy("Amount").SortByValue().Reverse()
+type GroupedCollection struct {
+	keyType  reflect.Type
+	elemType reflect.Type
+	keys     []reflect.Value
+	groups   []reflect.Value
+}
+
+// GroupBy groups the collection by one or more keys as accepted by Group,
+// ordered by key as by Pairs.SortByKey. Keys which cannot be ordered keep the
+// order of their first occurrence.
+func (c *Collection) GroupBy(keys ...interface{}) *GroupedCollection {
+	return must(c.GroupByE(keys...)).(*GroupedCollection)
+}
+
+func (c *Collection) GroupByE(keys ...interface{}) (res *GroupedCollection, err error) {
+	defer catch(&err)
+	v, err := sliceValue(c.collection)
+	if err != nil {
+		return nil, err
+	}
+	el := v.Type().Elem()
+	tp, getter, err := compositeGetter(el, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &GroupedCollection{keyType: tp, elemType: el}
+	index := map[interface{}]int{}
+	for i := 0; i < v.Len(); i++ {
+		k := getter(v.Index(i))
+		idx, ok := index[k.Interface()]
+		if !ok {
+			idx = len(g.keys)
+			index[k.Interface()] = idx
+			g.keys = append(g.keys, k)
+			g.groups = append(g.groups, reflect.MakeSlice(reflect.SliceOf(el), 0, 0))
+		}
+		g.groups[idx] = reflect.Append(g.groups[idx], v.Index(i))
+	}
+	g.sortByKey()
+	return g, nil
+}
+
+// sortByKey sorts the groups by key. Keys which cannot be ordered, such as
+// pointers to structs or interface keys of different types, keep the order of
+// their first occurrence.
+func (g *GroupedCollection) sortByKey() {
+	if g.keyType.Kind() == reflect.Interface && !sameType(g.keys) {
+		return
+	}
+	cmp, err := keyCompareFunc(g.keyType)
+	if err != nil {
+		return
+	}
+	key := &compiledSortKey{cmp: cmp}
+	sort.Stable(groupSorter{g, key})
+}
+
+// sameType reports whether the non-nil interface values all have the same
+// dynamic type, which can be ordered.
+func sameType(values []reflect.Value) bool {
+	var tp reflect.Type
+	for _, v := range values {
+		if v.IsNil() {
+			continue
+		}
+		if tp != nil && v.Elem().Type() != tp {
+			return false
+		}
+		tp = v.Elem().Type()
+	}
+	if tp == nil {
+		return true
+	}
+	_, err := keyCompareFunc(tp)
+	return err == nil
+}
+
+type groupSorter struct {
+	g   *GroupedCollection
+	key *compiledSortKey
+}
+
+func (s groupSorter) Len() int { return len(s.g.keys) }
+
+func (s groupSorter) Less(a, b int) bool { return s.key.compare(s.g.keys[a], s.g.keys[b]) < 0 }
+
+func (s groupSorter) Swap(a, b int) {
+	s.g.keys[a], s.g.keys[b] = s.g.keys[b], s.g.keys[a]
+	s.g.groups[a], s.g.groups[b] = s.g.groups[b], s.g.groups[a]
+}
+
+func (g *GroupedCollection) Len() int {
+	return len(g.keys)
+}
+
+// Groups returns the elements of every group as a slice of the original
+// type.
+func (g *GroupedCollection) Groups() Pairs {
+	return g.each(func(_ reflect.Value, group reflect.Value) interface{} {
+		return group.Interface()
+	})
+}
+
+func (g *GroupedCollection) Count() Pairs {
+	return g.each(func(_ reflect.Value, group reflect.Value) interface{} {
+		return group.Len()
+	})
+}
+
+func (g *GroupedCollection) SumBy(key interface{}) Pairs {
+	return g.each(func(_ reflect.Value, group reflect.Value) interface{} {
+		return SumBy(group.Interface(), key)
+	})
+}
+
+func (g *GroupedCollection) AverageBy(key interface{}) Pairs {
+	return g.each(func(_ reflect.Value, group reflect.Value) interface{} {
+		return AverageBy(group.Interface(), key)
+	})
+}
+
+// MaxBy returns the element with the largest key per group.
+func (g *GroupedCollection) MaxBy(key interface{}) Pairs {
+	return g.each(func(_ reflect.Value, group reflect.Value) interface{} {
+		el, _ := MaxBy(group.Interface(), key)
+		return el
+	})
+}
+
+// MinBy returns the element with the smallest key per group.
+func (g *GroupedCollection) MinBy(key interface{}) Pairs {
+	return g.each(func(_ reflect.Value, group reflect.Value) interface{} {
+		el, _ := MinBy(group.Interface(), key)
+		return el
+	})
+}
+
+// Aggregate calls fn, a func(K, []T) R, with every group and returns its
+// results.
+func (g *GroupedCollection) Aggregate(fn interface{}) Pairs {
+	fv := reflect.ValueOf(fn)
+	ft := reflect.TypeOf(fn)
+	if ft == nil || ft.Kind() != reflect.Func || ft.NumIn() != 2 || ft.NumOut() != 1 ||
+		!g.keyType.AssignableTo(ft.In(0)) || !reflect.SliceOf(g.elemType).AssignableTo(ft.In(1)) {
+		panic(badSignature(ft, fmt.Sprintf("expected func(%v, []%v) R", g.keyType, g.elemType)))
+	}
+	return g.each(func(key reflect.Value, group reflect.Value) interface{} {
+		return fv.Call([]reflect.Value{key, group})[0].Interface()
+	})
+}
+
+// FirstN keeps the first n elements of every group.
+func (g *GroupedCollection) FirstN(n int) *GroupedCollection {
+	return g.transform(func(group reflect.Value) reflect.Value {
+		return reflect.ValueOf(FirstN(group.Interface(), n))
+	})
+}
+
+// TopN keeps the n largest elements of every group by keys, see TopN.
+func (g *GroupedCollection) TopN(n int, keys ...interface{}) *GroupedCollection {
+	return g.transform(func(group reflect.Value) reflect.Value {
+		return reflect.ValueOf(TopN(group.Interface(), n, keys...))
+	})
+}
+
+// BottomN keeps the n smallest elements of every group by keys.
+func (g *GroupedCollection) BottomN(n int, keys ...interface{}) *GroupedCollection {
+	return g.transform(func(group reflect.Value) reflect.Value {
+		return reflect.ValueOf(BottomN(group.Interface(), n, keys...))
+	})
+}
+
+// Map maps the elements of every group with mapper, as Map does.
+func (g *GroupedCollection) Map(mapper interface{}) *GroupedCollection {
+	tp, _, err := newGetter(g.elemType, mapper)
+	if err != nil {
+		panic(err)
+	}
+	res := g.transform(func(group reflect.Value) reflect.Value {
+		return reflect.ValueOf(Map(group.Interface(), mapper))
+	})
+	res.elemType = tp
+	return res
+}
+
+func (g *GroupedCollection) transform(fn func(group reflect.Value) reflect.Value) *GroupedCollection {
+	res := &GroupedCollection{keyType: g.keyType, elemType: g.elemType, keys: g.keys, groups: make([]reflect.Value, len(g.groups))}
+	for i, group := range g.groups {
+		res.groups[i] = fn(group)
+	}
+	return res
+}
+
+func (g *GroupedCollection) each(fn func(key reflect.Value, group reflect.Value) interface{}) Pairs {
+	res := make(Pairs, len(g.keys))
+	for i, k := range g.keys {
+		res[i] = Pair{Key: k.Interface(), Value: fn(k, g.groups[i])}
+	}
+	return res
+}
@@ -0,0 +1,134 @@
+package generics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type groupedPayment struct {
+	AccountID int
+	Currency  string
+	Amount    float64
+}
+
+func groupedPayments() []*groupedPayment {
+	return []*groupedPayment{
+		{3, "EUR", 10},
+		{1, "EUR", 20},
+		{2, "USD", 5},
+		{1, "USD", 30},
+		{3, "EUR", 40},
+		{1, "EUR", 1},
+	}
+}
+
+func pairsString(p Pairs) string {
+	s := ""
+	for _, pair := range p {
+		switch v := pair.Value.(type) {
+		case *groupedPayment:
+			s += fmt.Sprintf("%v:%v ", pair.Key, v.Amount)
+		default:
+			s += fmt.Sprintf("%v:%v ", pair.Key, v)
+		}
+	}
+	return s
+}
+
+func TestGroupedCollection(t *testing.T) {
+	g := New(groupedPayments()).GroupBy("AccountID")
+
+	tests := []struct {
+		Has  Pairs
+		Want string
+	}{
+		{g.Count(), "1:3 2:1 3:2 "},
+		{g.SumBy("Amount"), "1:51 2:5 3:50 "},
+		{g.SumBy("Amount").SortByValue(), "2:5 3:50 1:51 "},
+		{g.SumBy("Amount").SortByValue().Reverse(), "1:51 3:50 2:5 "},
+		{g.AverageBy("Amount"), "1:17 2:5 3:25 "},
+		{g.MaxBy("Amount"), "1:30 2:5 3:40 "},
+		{g.MinBy(func(p *groupedPayment) float64 { return p.Amount }), "1:1 2:5 3:10 "},
+		{g.FirstN(1).MaxBy("Amount"), "1:20 2:5 3:10 "},
+		{g.Map("Currency").Groups(), "1:[EUR USD EUR] 2:[USD] 3:[EUR EUR] "},
+		{g.Aggregate(func(id int, list []*groupedPayment) string {
+			return fmt.Sprintf("%d payments", len(list))
+		}), "1:3 payments 2:1 payments 3:2 payments "},
+		{New(groupedPayments()).GroupBy("Currency").Count(), "EUR:4 USD:2 "},
+	}
+	for i, tc := range tests {
+		if has := pairsString(tc.Has); has != tc.Want {
+			t.Errorf("%d: want %q, was %q", i+1, tc.Want, has)
+		}
+	}
+	if g.Len() != 3 {
+		t.Errorf("expected 3 groups, was %d", g.Len())
+	}
+}
+
+func TestGroupedCollectionAggregateSignature(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	New(groupedPayments()).GroupBy("AccountID").Aggregate(func(id string, list []*groupedPayment) int { return 0 })
+}
+
+func TestGroupedCollectionCompositeKeyOrder(t *testing.T) {
+	g := New(groupedPayments()).GroupBy("Currency", "AccountID")
+	tests := []struct {
+		Has  Pairs
+		Want string
+	}{
+		{g.Count(), "{EUR 1}:2 {EUR 3}:2 {USD 1}:1 {USD 2}:1 "},
+		{g.Count().Reverse().SortByKey(), "{EUR 1}:2 {EUR 3}:2 {USD 1}:1 {USD 2}:1 "},
+		{Pairs{{nil, 1}, {"b", 2}, {"a", 3}}.SortByKey(), "a:3 b:2 <nil>:1 "},
+		{Pairs{{2, nil}, {1, 3}, {3, nil}}.SortByValue(), "1:3 2:<nil> 3:<nil> "},
+	}
+	for i, tc := range tests {
+		if has := pairsString(tc.Has); has != tc.Want {
+			t.Errorf("%d: want %q, was %q", i+1, tc.Want, has)
+		}
+	}
+}
+
+func TestGroupedCollectionUnorderedKeys(t *testing.T) {
+	type account struct{ Name string }
+	type payment struct {
+		Account *account
+		C       complex128
+		Any     interface{}
+		Amount  float64
+	}
+	b, a := &account{"b"}, &account{"a"}
+	list := []*payment{{b, 2, "x", 1}, {a, 1, 1, 2}, {b, 2, "x", 3}, {nil, 3, nil, 4}}
+	keys := func(g *GroupedCollection) string {
+		s := ""
+		for _, pair := range g.SumBy("Amount") {
+			s += fmt.Sprintf("%v ", pair.Value)
+		}
+		return s
+	}
+
+	tests := []struct{ Has, Want interface{} }{
+		{keys(New(list).GroupBy("Account")), "4 2 4 "},
+		{keys(New(list).GroupBy("C")), "4 2 4 "},
+		{keys(New(list).GroupBy("Amount", "C")), "1 2 3 4 "},
+		{keys(New(list).GroupBy("C", "Amount")), "1 2 3 4 "},
+		{keys(New(list).GroupBy("Any")), "4 2 4 "},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("expected ErrUnsupportedType, was %v", err)
+		}
+	}()
+	Pairs{{1, nil}, {"a", nil}}.SortByKey()
+}
@@ -1,55 +1,276 @@
 package generics
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 )
 
 func (c *Collection) Join(mapOrList interface{}) *Collection {
+	return mustCollection(c.JoinE(mapOrList))
+}
+
+// JoinE sets the field of every element of the collection which belongs to
+// an element of mapOrList, see JoinWithReport for the keys and a report of
+// the matches.
+func (c *Collection) JoinE(mapOrList interface{}) (*Collection, error) {
+	_, err := c.JoinWithReport(mapOrList)
+	return c, err
+}
+
+// JoinWithReport sets the field of every element of the collection which
+// belongs to an element of mapOrList and reports which elements matched. The
+// field, foreign and primary key are taken from a belongs_to tag on the
+// element type, see Association, or else by convention from the element
+// type name of mapOrList: Account, AccountID and ID for accounts.
+func (c *Collection) JoinWithReport(mapOrList interface{}) (*JoinReport, error) {
 	t := reflect.TypeOf(mapOrList)
+	if t == nil || (t.Kind() != reflect.Map && t.Kind() != reflect.Slice) {
+		return nil, unsupportedType(t, "expected second argument to be map or slice")
+	}
+	if sv, err := sliceValue(c.collection); err == nil && derefType(sv.Type().Elem()).Kind() == reflect.Struct {
+		a, err := belongsTo(sv.Type().Elem(), t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		if a != nil {
+			return c.JoinGenericWithReport(mapOrList, a.Field, a.ForeignKey, a.PrimaryKey)
+		}
+	}
 	name := nameFromMap(t)
-	return c.JoinGeneric(mapOrList, name, name+"ID", "ID")
+	return c.JoinGenericWithReport(mapOrList, name, name+"ID", "ID")
 }
 
 func (c *Collection) JoinGeneric(mapOrList interface{}, name, foreignKeyName, primaryKeyName string) *Collection {
+	return mustCollection(c.JoinGenericE(mapOrList, name, foreignKeyName, primaryKeyName))
+}
+
+// JoinGenericE sets the field name of every element of the collection to the
+// element of mapOrList with a matching key, see JoinGenericWithReport for how
+// keys are matched and a report of the matches.
+func (c *Collection) JoinGenericE(mapOrList interface{}, name, foreignKeyName, primaryKeyName string) (*Collection, error) {
+	_, err := c.JoinGenericWithReport(mapOrList, name, foreignKeyName, primaryKeyName)
+	return c, err
+}
+
+// JoinGenericWithReport sets the field name of every element of the
+// collection to the element of mapOrList whose primary key matches the
+// element's foreign key. mapOrList is either a slice, which is indexed by
+// primaryKeyName, or a map already indexed by the primary key.
+//
+// Foreign keys are converted to the primary key type if needed, so an int64
+// foreign key matches an int primary key. Pointer and sql.Null* foreign keys
+// are dereferenced, nil and NULL keys match nothing. Elements without a
+// match are set to the zero value and listed in the report, see also
+// LeftJoin.
+//
+// Composite keys are given as comma separated attributes, matched by
+// position:
+//
+//	c.JoinGenericWithReport(rates, "Rate", "Currency,Month", "Code,Month")
+func (c *Collection) JoinGenericWithReport(mapOrList interface{}, name, foreignKeyName, primaryKeyName string) (report *JoinReport, err error) {
+	defer catch(&err)
 	t := reflect.TypeOf(mapOrList)
+	if t == nil {
+		return nil, unsupportedType(t, "expected second argument to be map or slice")
+	}
 
 	mv := reflect.ValueOf(mapOrList)
-	sv := reflect.ValueOf(c.collection)
+	sv, err := sliceValue(c.collection)
+	if err != nil {
+		return nil, err
+	}
+	foreignKeys, primaryKeys, err := keyPair(sv.Type(), foreignKeyName, primaryKeyName)
+	if err != nil {
+		return nil, err
+	}
 	switch t.Kind() {
 	case reflect.Slice:
-		st := t.Elem()
-		if st.Kind() == reflect.Ptr {
-			st = st.Elem()
+		if derefType(t.Elem()).Kind() != reflect.Struct {
+			return nil, unsupportedType(t, "expected slice of structs")
 		}
-		f, _ := st.FieldByName(primaryKeyName)
-		newMV := reflect.MakeMap(reflect.MapOf(f.Type, t.Elem()))
-
-		for i := 0; i < mv.Len(); i++ {
-			el := mv.Index(i)
-			var key reflect.Value
-			if el.Kind() == reflect.Ptr {
-				key = el.Elem().FieldByName(primaryKeyName)
-			} else {
-				key = el.FieldByName(primaryKeyName)
-			}
-			newMV.SetMapIndex(key, el)
+		keyType, key, err := baseKeyGetter(t.Elem(), primaryKeys)
+		if err != nil {
+			return nil, err
 		}
-		mv = newMV
+		mv = indexByKey(mv, keyType, key, false)
 	case reflect.Map:
 		// ok
 	default:
-		panic("expected second argument to be map, was " + t.Kind().String())
+		return nil, unsupportedType(t, "expected second argument to be map or slice")
+	}
+
+	return setFromMap(sv, mv, name, foreignKeys)
+}
+
+// JoinReport describes a finished join by element index of the collection.
+// NullKeys are the elements with a nil or NULL foreign key, Unmatched those
+// whose key is in neither the map nor the list. Both are set to the zero
+// value.
+type JoinReport struct {
+	Matched   int
+	Unmatched []int
+	NullKeys  []int
+}
+
+// setFromMap sets the field name of every element of sv to the value of m at
+// the element's keys, or to the zero value if there is none.
+func setFromMap(sv, m reflect.Value, name string, keys []interface{}) (*JoinReport, error) {
+	el := sv.Type().Elem()
+	st := derefType(el)
+	if st.Kind() != reflect.Struct {
+		return nil, unsupportedType(sv.Type(), "expected collection of structs")
+	}
+	if el.Kind() != reflect.Ptr && sv.Kind() == reflect.Array && !sv.CanAddr() {
+		return nil, unsupportedType(sv.Type(), "expected slice or pointer to array")
 	}
+	key, err := matchingKeyGetter(el, keys, m.Type().Key())
+	if err != nil {
+		return nil, err
+	}
+	target, ok := st.FieldByName(name)
+	if !ok {
+		return nil, fieldNotFound(st, name)
+	}
+	if !m.Type().Elem().AssignableTo(target.Type) {
+		return nil, &Error{Kind: ErrUnsupportedType, Type: target.Type, Field: name,
+			Msg: fmt.Sprintf("cannot assign %v to %s.%s of type %v", m.Type().Elem(), st, name, target.Type)}
+	}
+
+	report := &JoinReport{}
 	for i := 0; i < sv.Len(); i++ {
 		v := sv.Index(i)
+		k, null := key(v)
+		var match reflect.Value
+		if k.IsValid() {
+			match = m.MapIndex(k)
+		}
+		switch {
+		case null:
+			report.NullKeys = append(report.NullKeys, i)
+		case match.IsValid():
+			report.Matched++
+		default:
+			report.Unmatched = append(report.Unmatched, i)
+		}
 		if v.Kind() == reflect.Ptr {
 			v = v.Elem()
 		}
-		account := mv.MapIndex(v.FieldByName(foreignKeyName))
-		v.FieldByName(name).Set(account)
+		if !match.IsValid() {
+			match = reflect.Zero(target.Type)
+		}
+		v.FieldByIndex(target.Index).Set(match)
+	}
+	return report, nil
+}
+
+// baseKeyGetter returns the type of keys on el without pointers and
+// sql.Null* wrappers, see keyBase, and a getter for them as
+// matchingKeyGetter.
+func baseKeyGetter(el reflect.Type, keys []interface{}) (reflect.Type, func(v reflect.Value) (reflect.Value, bool), error) {
+	tp, _, err := compositeGetter(el, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(keys) == 1 {
+		tp = keyBase(tp)
+	} else {
+		fields := make([]reflect.StructField, tp.NumField())
+		for i := range fields {
+			fields[i] = reflect.StructField{Name: tp.Field(i).Name, Type: keyBase(tp.Field(i).Type)}
+		}
+		tp = reflect.StructOf(fields)
+	}
+	getter, err := matchingKeyGetter(el, keys, tp)
+	return tp, getter, err
+}
+
+// indexByKey maps the elements of the slice lv by key, a getter as returned
+// by matchingKeyGetter, skipping nil and NULL keys. With many every key maps
+// to a slice of its elements, otherwise to the last one.
+func indexByKey(lv reflect.Value, keyType reflect.Type, key func(v reflect.Value) (reflect.Value, bool), many bool) reflect.Value {
+	valueType := lv.Type().Elem()
+	if many {
+		valueType = reflect.SliceOf(valueType)
+	}
+	m := reflect.MakeMap(reflect.MapOf(keyType, valueType))
+	for i := 0; i < lv.Len(); i++ {
+		el := lv.Index(i)
+		k, _ := key(el)
+		if !k.IsValid() {
+			continue
+		}
+		if many {
+			group := m.MapIndex(k)
+			if !group.IsValid() {
+				group = reflect.MakeSlice(valueType, 0, 1)
+			}
+			el = reflect.Append(group, el)
+		}
+		m.SetMapIndex(k, el)
+	}
+	return m
+}
+
+func splitKeys(names string) []interface{} {
+	var keys []interface{}
+	for _, name := range strings.Split(names, ",") {
+		keys = append(keys, strings.TrimSpace(name))
+	}
+	return keys
+}
+
+// matchingKeyGetter returns a getter for keys on el as a value of keyType,
+// the key type of the joined map, converted by keyConverter. Several keys
+// are set on the fields of keyType by position. The getter reports null if
+// any key is null and returns an invalid key if any key cannot be converted.
+func matchingKeyGetter(el reflect.Type, keys []interface{}, keyType reflect.Type) (func(v reflect.Value) (reflect.Value, bool), error) {
+	types, getters, err := keyGetters(el, keys)
+	if err != nil {
+		return nil, err
+	}
+	want := []reflect.Type{keyType}
+	if len(keys) > 1 {
+		if keyType.Kind() != reflect.Struct || keyType.NumField() != len(keys) {
+			return nil, unsupportedType(keyType, fmt.Sprintf("expected key with %d fields", len(keys)))
+		}
+		want = make([]reflect.Type, len(keys))
+		for i := range want {
+			want[i] = keyType.Field(i).Type
+		}
+	}
+	convs := make([]func(v reflect.Value) (reflect.Value, bool), len(keys))
+	for i, tp := range types {
+		conv, ok := keyConverter(tp, want[i])
+		if !ok {
+			return nil, &Error{Kind: ErrUnsupportedType, Type: tp, Field: fmt.Sprint(keys[i]),
+				Msg: fmt.Sprintf("key %s.%s of type %v does not match key type %v", el, keys[i], tp, want[i])}
+		}
+		convs[i] = conv
+	}
+	if len(keys) == 1 {
+		return func(v reflect.Value) (reflect.Value, bool) {
+			return convs[0](getters[0](v))
+		}, nil
+	}
+	return func(v reflect.Value) (reflect.Value, bool) {
+		key := reflect.New(keyType).Elem()
+		for i, g := range getters {
+			k, null := convs[i](g(v))
+			if null || !k.IsValid() {
+				return reflect.Value{}, null
+			}
+			key.Field(i).Set(k)
+		}
+		return key, false
+	}, nil
+}
+
+func mustCollection(res *Collection, err error) *Collection {
+	if err != nil {
+		panic(err)
 	}
-	return c
+	return res
 }
 
 func nameFromMap(in reflect.Type) string {
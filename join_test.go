@@ -1,6 +1,9 @@
 package generics
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -55,3 +58,87 @@ func TestJoinWithSlice(t *testing.T) {
 		}
 	}
 }
+
+func TestJoinNormalizesKeys(t *testing.T) {
+	type wide struct {
+		AccountID int64
+		Account   *Account
+	}
+	type optional struct {
+		AccountID *int
+		Account   *Account
+	}
+	type nullable struct {
+		AccountID sql.NullInt64
+		Account   *Account
+	}
+	type value struct {
+		AccountID uint8
+		Account   Account
+	}
+	accounts := []*Account{{1, "one"}, {2, "two"}}
+	one := 1
+
+	wides := []*wide{{AccountID: 2}, {AccountID: 3}}
+	wideReport, err := New(wides).JoinWithReport(accounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	optionals := []optional{{AccountID: &one}, {}}
+	optionalReport, err := New(optionals).JoinWithReport(accounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nullables := []*nullable{{AccountID: sql.NullInt64{Int64: 2, Valid: true}}, {AccountID: sql.NullInt64{Int64: 1}}}
+	nullableReport, err := New(nullables).JoinGenericWithReport(Index(accounts, "ID"), "Account", "AccountID", "ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := []value{{AccountID: 1}}
+	New(values).JoinGeneric([]Account{{1, "one"}}, "Account", "AccountID", "ID")
+
+	tests := []struct{ Has, Want interface{} }{
+		{wides[0].Account.Name, "two"},
+		{wides[1].Account == nil, true},
+		{fmt.Sprint(wideReport.Matched, wideReport.Unmatched, wideReport.NullKeys), "1 [1] []"},
+		{optionals[0].Account.Name, "one"},
+		{optionals[1].Account == nil, true},
+		{fmt.Sprint(optionalReport.Matched, optionalReport.Unmatched, optionalReport.NullKeys), "1 [] [1]"},
+		{nullables[0].Account.Name, "two"},
+		{nullables[1].Account == nil, true},
+		{fmt.Sprint(nullableReport.Matched, nullableReport.Unmatched, nullableReport.NullKeys), "1 [] [1]"},
+		{values[0].Account.Name, "one"},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestJoinValidatesUpFront(t *testing.T) {
+	type named struct {
+		AccountID string
+		Account   *Account
+	}
+	type wrongTarget struct {
+		AccountID int
+		Account   string
+	}
+	list := []*wrongTarget{{AccountID: 1, Account: "kept"}}
+	_, errKey := New([]*named{{AccountID: "1"}}).JoinE([]*Account{{1, "one"}})
+	_, errTarget := New(list).JoinE([]*Account{{1, "one"}})
+	_, errArray := New([1]Payment{{AccountID: 1}}).JoinE([]*Account{{1, "one"}})
+
+	tests := []struct{ Has, Want interface{} }{
+		{errors.Is(errKey, ErrUnsupportedType), true},
+		{errors.Is(errTarget, ErrUnsupportedType), true},
+		{list[0].Account, "kept"},
+		{errors.Is(errArray, ErrUnsupportedType), true},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
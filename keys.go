@@ -0,0 +1,252 @@
+package generics
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+	"strings"
+)
+
+// CompositeKey returns the key type Group and Index use for the given keys
+// on elements of type el. A single key is used as is, several keys are
+// combined into an unnamed struct with one field per key. Fields are named
+// after the attribute path without dots ("Account.Name" becomes AccountName),
+// or K0, K1, ... by position for funcs and paths which are not exported
+// identifiers.
+func CompositeKey(el reflect.Type, keys ...interface{}) (reflect.Type, error) {
+	tp, _, err := compositeGetter(el, keys)
+	return tp, err
+}
+
+func compositeGetter(el reflect.Type, keys []interface{}) (reflect.Type, func(v reflect.Value) reflect.Value, error) {
+	types, getters, err := keyGetters(el, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(keys) == 1 {
+		return types[0], getters[0], nil
+	}
+
+	fields := make([]reflect.StructField, len(keys))
+	seen := map[string]bool{}
+	for i, k := range keys {
+		name := keyFieldName(k, i)
+		if seen[name] {
+			return nil, nil, &Error{Kind: ErrAmbiguous, Type: el, Field: name, Msg: fmt.Sprintf("key %s used twice", name)}
+		}
+		seen[name] = true
+		fields[i] = reflect.StructField{Name: name, Type: types[i]}
+	}
+	tp := reflect.StructOf(fields)
+	return tp, func(v reflect.Value) reflect.Value {
+		key := reflect.New(tp).Elem()
+		for i, g := range getters {
+			key.Field(i).Set(g(v))
+		}
+		return key
+	}, nil
+}
+
+// keyGetters compiles keys, which must all be comparable.
+func keyGetters(el reflect.Type, keys []interface{}) ([]reflect.Type, []func(v reflect.Value) reflect.Value, error) {
+	if len(keys) == 0 {
+		return nil, nil, &Error{Kind: ErrBadSignature, Type: el, Msg: "expected at least one key"}
+	}
+	types := make([]reflect.Type, len(keys))
+	getters := make([]func(v reflect.Value) reflect.Value, len(keys))
+	for i, k := range keys {
+		tp, getter, err := newGetter(el, k)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !tp.Comparable() {
+			return nil, nil, unsupportedType(tp, "expected comparable key")
+		}
+		types[i], getters[i] = tp, getter
+	}
+	return types, getters, nil
+}
+
+func keyFieldName(key interface{}, i int) string {
+	var path string
+	switch k := key.(type) {
+	case string:
+		path = k
+	case Attribute:
+		path = k.Path
+	}
+	name := strings.ReplaceAll(path, ".", "")
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		return fmt.Sprintf("K%d", i)
+	}
+	return name
+}
+
+// GroupNested groups the elements of i by every key in turn, into a
+// map[K1]map[K2]...[]T.
+func GroupNested(i interface{}, keys ...interface{}) interface{} {
+	return must(GroupNestedE(i, keys...))
+}
+
+func GroupNestedE(i interface{}, keys ...interface{}) (interface{}, error) {
+	return nested(i, keys, true)
+}
+
+// IndexNested maps the elements of i by every key in turn, into a
+// map[K1]map[K2]...T.
+func IndexNested(i interface{}, keys ...interface{}) interface{} {
+	return must(IndexNestedE(i, keys...))
+}
+
+func IndexNestedE(i interface{}, keys ...interface{}) (interface{}, error) {
+	return nested(i, keys, false)
+}
+
+func nested(i interface{}, keys []interface{}, group bool) (res interface{}, err error) {
+	defer catch(&err)
+	v, err := sliceValue(i)
+	if err != nil {
+		return nil, err
+	}
+	el := v.Type().Elem()
+	types, getters, err := keyGetters(el, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	// mapTypes[k] is the type of the maps on level k
+	leaf := el
+	if group {
+		leaf = reflect.SliceOf(el)
+	}
+	mapTypes := make([]reflect.Type, len(keys))
+	for k := len(keys) - 1; k >= 0; k-- {
+		mapTypes[k] = reflect.MapOf(types[k], leaf)
+		leaf = mapTypes[k]
+	}
+
+	root := reflect.MakeMap(mapTypes[0])
+	last := len(keys) - 1
+	for idx := 0; idx < v.Len(); idx++ {
+		el := v.Index(idx)
+		m := root
+		for k := 0; k < last; k++ {
+			key := getters[k](el)
+			child := m.MapIndex(key)
+			if !child.IsValid() {
+				child = reflect.MakeMap(mapTypes[k+1])
+				m.SetMapIndex(key, child)
+			}
+			m = child
+		}
+		key := getters[last](el)
+		if !group {
+			m.SetMapIndex(key, el)
+			continue
+		}
+		list := m.MapIndex(key)
+		if !list.IsValid() {
+			list = reflect.MakeSlice(mapTypes[last].Elem(), 0, 1)
+		}
+		m.SetMapIndex(key, reflect.Append(list, el))
+	}
+	return root.Interface(), nil
+}
+
+// keyConverter returns a func converting keys of type from to type to, or
+// false if they cannot be. Pointers and sql.Null* like structs are unwrapped
+// first, a nil or not Valid key returning null, so two nil keys never match.
+// Only if the unwrapped key does not convert, as for a map indexed by
+// pointers, are they kept as they are. Otherwise it handles assignable types
+// and conversions between numeric or between string kinds. A key which does
+// not survive the conversion, like 1.5 to int, is returned invalid.
+func keyConverter(from, to reflect.Type) (func(v reflect.Value) (key reflect.Value, null bool), bool) {
+	switch {
+	case from.Kind() == reflect.Ptr:
+		conv, ok := keyConverter(from.Elem(), to)
+		if !ok && from.AssignableTo(to) {
+			return func(v reflect.Value) (reflect.Value, bool) {
+				if v.IsNil() {
+					return reflect.Value{}, true
+				}
+				return v, false
+			}, true
+		}
+		return func(v reflect.Value) (reflect.Value, bool) {
+			if v.IsNil() {
+				return reflect.Value{}, true
+			}
+			return conv(v.Elem())
+		}, ok
+	case from.Kind() == reflect.Struct && keyBase(from) != from:
+		valid, value, _ := nullableFields(from)
+		conv, ok := keyConverter(from.Field(value).Type, to)
+		if !ok && from.AssignableTo(to) {
+			return func(v reflect.Value) (reflect.Value, bool) {
+				if !v.Field(valid).Bool() {
+					return reflect.Value{}, true
+				}
+				return v, false
+			}, true
+		}
+		return func(v reflect.Value) (reflect.Value, bool) {
+			if !v.Field(valid).Bool() {
+				return reflect.Value{}, true
+			}
+			return conv(v.Field(value))
+		}, ok
+	case from.AssignableTo(to):
+		return func(v reflect.Value) (reflect.Value, bool) { return v, false }, true
+	case numericKind(from) && numericKind(to), from.Kind() == reflect.String && to.Kind() == reflect.String:
+		return func(v reflect.Value) (reflect.Value, bool) {
+			key := v.Convert(to)
+			if key.Convert(from).Interface() != v.Interface() {
+				return reflect.Value{}, false
+			}
+			return key, false
+		}, true
+	}
+	return nil, false
+}
+
+// keyBase returns the type keys of type t are matched as, without pointers
+// and sql.Null* wrappers.
+func keyBase(t reflect.Type) reflect.Type {
+	for {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+			continue
+		}
+		if t.Kind() == reflect.Struct {
+			if _, value, ok := nullableFields(t); ok {
+				t = t.Field(value).Type
+				continue
+			}
+		}
+		return t
+	}
+}
+
+// nullableFields returns the Valid and the value field index of a struct
+// shaped like sql.NullInt64 or sql.Null[T].
+func nullableFields(t reflect.Type) (valid, value int, ok bool) {
+	if t.NumField() != 2 {
+		return 0, 0, false
+	}
+	for i := 0; i < 2; i++ {
+		if f := t.Field(i); f.Name == "Valid" && f.Type.Kind() == reflect.Bool {
+			return i, 1 - i, t.Field(1 - i).IsExported()
+		}
+	}
+	return 0, 0, false
+}
+
+func numericKind(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
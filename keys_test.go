@@ -0,0 +1,196 @@
+package generics
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type accountCurrency struct {
+	AccountID int
+	Currency  string
+}
+
+func TestCompositeGroupAndIndex(t *testing.T) {
+	groups := Group(groupedPayments(), "AccountID", "Currency").(map[struct {
+		AccountID int
+		Currency  string
+	}][]*groupedPayment)
+	index := Index(groupedPayments(), "AccountID", func(p *groupedPayment) string { return p.Currency }).(map[struct {
+		AccountID int
+		K1        string
+	}]*groupedPayment)
+
+	tests := []struct{ Has, Want interface{} }{
+		{len(groups), 4},
+		{len(groups[accountCurrency{1, "EUR"}]), 2},
+		{groups[accountCurrency{3, "EUR"}][1].Amount, 40.0},
+		{len(index), 4},
+		{index[struct {
+			AccountID int
+			K1        string
+		}{1, "EUR"}].Amount, 1.0},
+		{len(New(groupedPayments()).GroupBy("AccountID", "Currency").Groups()), 4},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestNested(t *testing.T) {
+	groups := GroupNested(groupedPayments(), "Currency", "AccountID").(map[string]map[int][]*groupedPayment)
+	index := IndexNested(groupedPayments(), "Currency", "AccountID").(map[string]map[int]*groupedPayment)
+
+	tests := []struct{ Has, Want interface{} }{
+		{len(groups), 2},
+		{len(groups["EUR"]), 2},
+		{len(groups["EUR"][1]), 2},
+		{len(groups["USD"][2]), 1},
+		{index["EUR"][3].Amount, 40.0},
+		{index["USD"][1].Amount, 30.0},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestCompositeKey(t *testing.T) {
+	el := reflect.TypeOf(&Payment{})
+	name := func(p *Payment) string { return p.Account.Name }
+	tests := []struct {
+		Keys []interface{}
+		Want string
+		Err  error
+	}{
+		{[]interface{}{"ID"}, "int", nil},
+		{[]interface{}{"ID", "Account.Name"}, "struct { ID int; AccountName string }", nil},
+		{[]interface{}{"ID", name}, "struct { ID int; K1 string }", nil},
+		{[]interface{}{Attribute{Path: "Account.ID"}, "ID"}, "struct { AccountID int; ID int }", nil},
+		{nil, "", ErrBadSignature},
+		{[]interface{}{"ID", "ID"}, "", ErrAmbiguous},
+		{[]interface{}{"ID", "Missing"}, "", ErrFieldNotFound},
+		{[]interface{}{func(p *Payment) []int { return nil }}, "", ErrUnsupportedType},
+	}
+	for i, tc := range tests {
+		tp, err := CompositeKey(el, tc.Keys...)
+		if !errors.Is(err, tc.Err) {
+			t.Errorf("%d: want error %v, was %v", i+1, tc.Err, err)
+			continue
+		}
+		if err == nil && tp.String() != tc.Want {
+			t.Errorf("%d: want %s, was %s", i+1, tc.Want, tp)
+		}
+	}
+}
+
+func TestJoinCompositeKey(t *testing.T) {
+	type rate struct {
+		AccountID int
+		Currency  string
+		Rate      float64
+	}
+	type payment struct {
+		AccountID int
+		Currency  string
+		Rate      *rate
+	}
+	payments := []*payment{{1, "EUR", nil}, {1, "USD", nil}, {2, "EUR", nil}}
+	rates := []*rate{{1, "EUR", 1.5}, {1, "USD", 2}}
+
+	_, err := New(payments).JoinGenericE(rates, "Rate", "AccountID, Currency", "AccountID,Currency")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, errCount := New(payments).JoinGenericE(rates, "Rate", "AccountID", "AccountID,Currency")
+	_, errType := New(payments).JoinGenericE(rates, "Rate", "Currency,AccountID", "AccountID,Currency")
+
+	tests := []struct{ Has, Want interface{} }{
+		{payments[0].Rate.Rate, 1.5},
+		{payments[1].Rate.Rate, 2.0},
+		{payments[2].Rate == nil, true},
+		{errors.Is(errCount, ErrBadSignature), true},
+		{errors.Is(errType, ErrUnsupportedType), true},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestJoinPointerKeys(t *testing.T) {
+	type parent struct {
+		Key  *int
+		Name string
+	}
+	type child struct {
+		ParentKey *int
+		Parent    *parent
+	}
+	one, alsoOne := 1, 1
+	parents := []*parent{{nil, "none"}, {&one, "one"}}
+	children := []*child{{ParentKey: nil}, {ParentKey: &alsoOne}}
+	report, err := New(children).JoinGenericWithReport(parents, "Parent", "ParentKey", "Key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct{ Has, Want interface{} }{
+		{children[0].Parent == nil, true},
+		{children[1].Parent.Name, "one"},
+		{fmt.Sprint(report.NullKeys), "[0]"},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestKeyConverter(t *testing.T) {
+	type id int
+	seven := int64(7)
+	tests := []struct {
+		From interface{}
+		To   interface{}
+		Want string
+	}{
+		{7, 0, "7 false"},
+		{int64(7), 0, "7 false"},
+		{id(7), 0, "7 false"},
+		{7.0, 0, "7 false"},
+		{7.5, 0, "<invalid reflect.Value> false"},
+		{300, uint8(0), "<invalid reflect.Value> false"},
+		{&seven, 0, "7 false"},
+		{(*int64)(nil), 0, "<invalid reflect.Value> true"},
+		{sql.NullInt64{Int64: 7, Valid: true}, 0, "7 false"},
+		{sql.NullInt64{Int64: 7}, 0, "<invalid reflect.Value> true"},
+		{sql.NullString{String: "a", Valid: true}, "", "a false"},
+		{sql.Null[int32]{V: 7, Valid: true}, 0, "7 false"},
+		{(*int64)(nil), (*int64)(nil), "<invalid reflect.Value> true"},
+		{sql.NullInt64{}, sql.NullInt64{}, "<invalid reflect.Value> true"},
+	}
+	for i, tc := range tests {
+		conv, ok := keyConverter(reflect.TypeOf(tc.From), reflect.TypeOf(tc.To))
+		if !ok {
+			t.Errorf("%d: want converter from %T to %T", i+1, tc.From, tc.To)
+			continue
+		}
+		key, null := conv(reflect.ValueOf(tc.From))
+		if has := fmt.Sprint(key, " ", null); has != tc.Want {
+			t.Errorf("%d: want %q, was %q", i+1, tc.Want, has)
+		}
+	}
+
+	for i, tc := range [][2]interface{}{{1, ""}, {"1", 0}, {struct{ A, B int }{}, 0}, {[]int{}, 0}} {
+		if _, ok := keyConverter(reflect.TypeOf(tc[0]), reflect.TypeOf(tc[1])); ok {
+			t.Errorf("%d: want no converter from %T to %T", i+1, tc[0], tc[1])
+		}
+	}
+}
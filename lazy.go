@@ -0,0 +1,262 @@
+package generics
+
+import "reflect"
+
+// Lazy returns a lazy pipeline over the collection. Map, Select and Reject
+// are only recorded and run fused, element by element, when a terminal method
+// (Cast, Collect, ToMap, First, FirstN, Find, Any, Len, TopN, BottomN) is
+// called. First, FirstN, Find and Any stop as soon as they have their result:
+//
+//	New(list).Lazy().Select(fn).FirstN(2) // stops after the second match
+//
+// An invalid Map, Select or Reject is reported by the terminal method: the E
+// variants return the error, the others panic with it.
+func (c *Collection) Lazy() *LazyCollection {
+	return must(c.LazyE()).(*LazyCollection)
+}
+
+func (c *Collection) LazyE() (*LazyCollection, error) {
+	v, err := sliceValue(c.collection)
+	if err != nil {
+		return nil, err
+	}
+	return &LazyCollection{source: v, tp: v.Type().Elem()}, nil
+}
+
+type LazyCollection struct {
+	source reflect.Value
+	stages []stage
+	tp     reflect.Type // element type after all stages
+	err    error        // first error of a stage, returned by the terminals
+}
+
+// stage transforms v and reports whether it is kept.
+type stage func(v reflect.Value) (res reflect.Value, keep bool)
+
+func (l *LazyCollection) with(s stage, tp reflect.Type) *LazyCollection {
+	stages := append(l.stages[:len(l.stages):len(l.stages)], s)
+	return &LazyCollection{source: l.source, stages: stages, tp: tp}
+}
+
+func (l *LazyCollection) withErr(err error) *LazyCollection {
+	return &LazyCollection{source: l.source, stages: l.stages, tp: l.tp, err: err}
+}
+
+func (l *LazyCollection) Map(mapper interface{}) *LazyCollection {
+	if l.err != nil {
+		return l
+	}
+	tp, getter, err := newGetter(l.tp, mapper)
+	if err != nil {
+		return l.withErr(err)
+	}
+	return l.with(func(v reflect.Value) (reflect.Value, bool) {
+		return getter(v), true
+	}, tp)
+}
+
+func (l *LazyCollection) Select(fn interface{}) *LazyCollection {
+	return l.filter(fn, true)
+}
+
+func (l *LazyCollection) Reject(fn interface{}) *LazyCollection {
+	return l.filter(fn, false)
+}
+
+func (l *LazyCollection) filter(fn interface{}, want bool) *LazyCollection {
+	if l.err != nil {
+		return l
+	}
+	fun, err := filterFunc(l.tp, fn)
+	if err != nil {
+		return l.withErr(err)
+	}
+	return l.with(func(v reflect.Value) (reflect.Value, bool) {
+		return v, fun.Call([]reflect.Value{v})[0].Bool() == want
+	}, l.tp)
+}
+
+// each calls fn with every element passing all stages until fn returns
+// false.
+func (l *LazyCollection) each(fn func(v reflect.Value) bool) {
+outer:
+	for i := 0; i < l.source.Len(); i++ {
+		v := l.source.Index(i)
+		for _, s := range l.stages {
+			var keep bool
+			if v, keep = s(v); !keep {
+				continue outer
+			}
+		}
+		if !fn(v) {
+			return
+		}
+	}
+}
+
+// take materializes at most n elements, all of them when n is negative.
+func (l *LazyCollection) take(n int) reflect.Value {
+	size := 0
+	if len(l.stages) == 0 || n >= 0 {
+		// the length is known up front or bounded by n
+		size = l.source.Len()
+		if n >= 0 && n < size {
+			size = n
+		}
+	}
+	out := reflect.MakeSlice(reflect.SliceOf(l.tp), 0, size)
+	if n == 0 {
+		return out
+	}
+	l.each(func(v reflect.Value) bool {
+		out = reflect.Append(out, v)
+		return out.Len() != n
+	})
+	return out
+}
+
+func (l *LazyCollection) Cast() interface{} {
+	return must(l.CastE())
+}
+
+func (l *LazyCollection) CastE() (res interface{}, err error) {
+	defer catch(&err)
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.take(-1).Interface(), nil
+}
+
+func (l *LazyCollection) Collect() *Collection {
+	return New(l.Cast())
+}
+
+func (l *LazyCollection) CollectE() (*Collection, error) {
+	return collectionE(l.CastE())
+}
+
+// ToMap materializes the pipeline into a map indexed by key, like Index.
+func (l *LazyCollection) ToMap(key interface{}) interface{} {
+	return must(l.ToMapE(key))
+}
+
+func (l *LazyCollection) ToMapE(key interface{}) (res interface{}, err error) {
+	defer catch(&err)
+	if l.err != nil {
+		return nil, l.err
+	}
+	tp, getter, err := newGetter(l.tp, key)
+	if err != nil {
+		return nil, err
+	}
+	if !tp.Comparable() {
+		return nil, unsupportedType(tp, "expected comparable map key")
+	}
+	m := reflect.MakeMap(reflect.MapOf(tp, l.tp))
+	l.each(func(v reflect.Value) bool {
+		m.SetMapIndex(getter(v), v)
+		return true
+	})
+	return m.Interface(), nil
+}
+
+func (l *LazyCollection) First() interface{} {
+	return must(l.FirstE())
+}
+
+func (l *LazyCollection) FirstE() (res interface{}, err error) {
+	defer catch(&err)
+	if l.err != nil {
+		return nil, l.err
+	}
+	first := l.take(1)
+	if first.Len() == 0 {
+		return nullType(l.tp), nil
+	}
+	return first.Index(0).Interface(), nil
+}
+
+func (l *LazyCollection) FirstN(n int) *Collection {
+	return mustCollection(l.FirstNE(n))
+}
+
+func (l *LazyCollection) FirstNE(n int) (res *Collection, err error) {
+	defer catch(&err)
+	if l.err != nil {
+		return nil, l.err
+	}
+	return New(l.take(clamp(n, l.source.Len())).Interface()), nil
+}
+
+// Find returns the first element for which fn returns true.
+func (l *LazyCollection) Find(fn interface{}) interface{} {
+	return l.Select(fn).First()
+}
+
+func (l *LazyCollection) FindE(fn interface{}) (interface{}, error) {
+	return l.Select(fn).FirstE()
+}
+
+// Any reports whether fn returns true for any element.
+func (l *LazyCollection) Any(fn interface{}) bool {
+	return must(l.AnyE(fn)).(bool)
+}
+
+func (l *LazyCollection) AnyE(fn interface{}) (bool, error) {
+	n, err := l.Select(fn).FirstNE(1)
+	if err != nil {
+		return false, err
+	}
+	return n.Len() > 0, nil
+}
+
+func (l *LazyCollection) Len() int {
+	return must(l.LenE()).(int)
+}
+
+func (l *LazyCollection) LenE() (n int, err error) {
+	defer catch(&err)
+	if l.err != nil {
+		return 0, l.err
+	}
+	l.each(func(reflect.Value) bool {
+		n++
+		return true
+	})
+	return n, nil
+}
+
+// TopN returns the n largest elements by keys, see TopN. Only n elements are
+// kept while the pipeline runs.
+func (l *LazyCollection) TopN(n int, keys ...interface{}) *Collection {
+	return mustCollection(l.TopNE(n, keys...))
+}
+
+func (l *LazyCollection) TopNE(n int, keys ...interface{}) (*Collection, error) {
+	return l.top(n, keys, false)
+}
+
+// BottomN returns the n smallest elements by keys, see BottomN.
+func (l *LazyCollection) BottomN(n int, keys ...interface{}) *Collection {
+	return mustCollection(l.BottomNE(n, keys...))
+}
+
+func (l *LazyCollection) BottomNE(n int, keys ...interface{}) (*Collection, error) {
+	return l.top(n, keys, true)
+}
+
+func (l *LazyCollection) top(n int, keys []interface{}, bottom bool) (res *Collection, err error) {
+	defer catch(&err)
+	if l.err != nil {
+		return nil, l.err
+	}
+	t, err := newTopHeap(l.tp, n, keys, bottom)
+	if err != nil {
+		return nil, err
+	}
+	l.each(func(v reflect.Value) bool {
+		t.push(v)
+		return true
+	})
+	return New(t.result().Interface()), nil
+}
@@ -0,0 +1,85 @@
+package generics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	list := []*record{
+		{Name: "one", Amount: 1},
+		{Name: "two", Amount: 2},
+		{Name: "three", Amount: 3},
+		{Name: "four", Amount: 4},
+		{Name: "five", Amount: 5},
+	}
+	calls := 0
+	even := func(r *record) bool {
+		calls++
+		return r.Amount%2 == 0
+	}
+	lazy := New(list).Lazy()
+
+	tests := []struct {
+		Run   func() interface{}
+		Want  string
+		Calls int
+	}{
+		{func() interface{} { return lazy.Select(even).Map("Name").FirstN(1).Cast() }, "[two]", 2},
+		{func() interface{} { return lazy.Select(even).Map("Name").Cast() }, "[two four]", 5},
+		{func() interface{} { return lazy.Reject(even).Map("Name").FirstN(2).Cast() }, "[one three]", 3},
+		{func() interface{} { return lazy.Map("Amount").Select(func(v int) bool { return v > 3 }).First() }, "4", 0},
+		{func() interface{} { return lazy.Select(even).Map("Name").First() }, "two", 2},
+		{func() interface{} {
+			return lazy.Select(even).Find(func(r *record) bool { return r.Amount > 2 }).(*record).Name
+		}, "four", 4},
+		{func() interface{} { return lazy.Any(even) }, "true", 2},
+		{func() interface{} { return lazy.Select(even).Any(func(r *record) bool { return r.Amount > 10 }) }, "false", 5},
+		{func() interface{} { return lazy.Select(even).Len() }, "2", 5},
+		{func() interface{} { return lazy.Select(even).Collect().Len() }, "2", 5},
+		{func() interface{} { return lazy.Select(func(r *record) bool { return false }).First() == nil }, "true", 0},
+		{func() interface{} { return lazy.Map("Name").FirstN(-1).Cast() }, "[]", 0},
+		{func() interface{} { return lazy.Select(even).ToMap("Name").(map[string]*record)["four"].Amount }, "4", 5},
+	}
+	for i, tc := range tests {
+		calls = 0
+		if has := fmt.Sprint(tc.Run()); has != tc.Want {
+			t.Errorf("%d: want %s, was %s", i+1, tc.Want, has)
+		}
+		if calls != tc.Calls {
+			t.Errorf("%d: want %d calls, was %d", i+1, tc.Calls, calls)
+		}
+	}
+}
+
+func TestLazyE(t *testing.T) {
+	list := []*record{{Name: "one", Amount: 1}, {Name: "two", Amount: 2}}
+	lazy := New(list).Lazy()
+
+	_, errLazy := New(1).LazyE()
+	_, errMap := lazy.Map("Missing").Select(func(string) bool { return true }).CastE()
+	_, errSelect := lazy.Select(func(r record) bool { return true }).FirstE()
+	_, errAny := lazy.AnyE(func(r *record) int { return 0 })
+	_, errLen := lazy.Map("Missing").LenE()
+	_, errToMap := lazy.ToMapE("Missing")
+	_, errTop := lazy.TopNE(1, "Missing")
+	names, errNone := lazy.Map("Name").CollectE()
+
+	tests := []struct{ Has, Want interface{} }{
+		{errors.Is(errLazy, ErrUnsupportedType), true},
+		{errors.Is(errMap, ErrFieldNotFound), true},
+		{errors.Is(errSelect, ErrBadSignature), true},
+		{errors.Is(errAny, ErrBadSignature), true},
+		{errors.Is(errLen, ErrFieldNotFound), true},
+		{errors.Is(errToMap, ErrFieldNotFound), true},
+		{errors.Is(errTop, ErrFieldNotFound), true},
+		{errNone, nil},
+		{fmt.Sprint(names.Cast()), "[one two]"},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
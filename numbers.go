@@ -0,0 +1,135 @@
+package generics
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// NumberOption changes how Numbers converts elements.
+type NumberOption func(*numberOptions)
+
+type numberOptions struct {
+	parseStrings bool
+	nilAsError   bool
+}
+
+// ParseStrings parses elements of string kind as numbers instead of
+// reporting them as non-numeric.
+func ParseStrings() NumberOption {
+	return func(o *numberOptions) { o.parseStrings = true }
+}
+
+// NilAsError reports nil pointers and interfaces instead of skipping them.
+func NilAsError() NumberOption {
+	return func(o *numberOptions) { o.nilAsError = true }
+}
+
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
+// Numbers converts a slice of numbers to float64. Elements may be of any
+// integer or float kind, including named types, json.Number and pointers to
+// any of them. Nil pointers are skipped unless NilAsError is given, strings
+// are only parsed with ParseStrings. Any other element is reported as
+// ErrUnsupportedType. A []float64 is returned as is.
+//
+// The statistics functions convert their input the same way without options
+// and panic on non-numeric elements. Their E variants take the options and
+// return the error:
+//
+//	total, err := SumE(amounts, ParseStrings())
+func Numbers(in interface{}, opts ...NumberOption) ([]float64, error) {
+	switch c := in.(type) {
+	case []float64:
+		return c, nil
+	case []int:
+		out := make([]float64, len(c))
+		for i, v := range c {
+			out[i] = float64(v)
+		}
+		return out, nil
+	}
+
+	o := newNumberOptions(opts)
+	v, err := sliceValue(in)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]float64, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		f, ok, err := toFloat(v.Index(i), o)
+		if err != nil {
+			return nil, elementError(err, i)
+		}
+		if ok {
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+func toFloats(in interface{}) []float64 {
+	out, err := Numbers(in)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+func newNumberOptions(opts []NumberOption) *numberOptions {
+	o := &numberOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// toFloat converts v to a float64. ok is false for skipped nil values.
+func toFloat(v reflect.Value, o *numberOptions) (f float64, ok bool, err error) {
+	v, ok, err = deref(v, o)
+	if !ok {
+		return 0, false, err
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true, nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true, nil
+	case reflect.String:
+		if v.Type() != jsonNumberType && !o.parseStrings {
+			break
+		}
+		f, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return 0, false, &Error{Kind: ErrUnsupportedType, Type: v.Type(), Msg: fmt.Sprintf("%q is not a number", v.String())}
+		}
+		return f, true, nil
+	}
+	return 0, false, unsupportedType(v.Type(), "expected number")
+}
+
+// deref follows pointers and interfaces. ok is false for nil values, which
+// are an error with NilAsError.
+func deref(v reflect.Value, o *numberOptions) (reflect.Value, bool, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			if o.nilAsError {
+				return v, false, &Error{Kind: ErrUnsupportedType, Type: v.Type(), Msg: "nil is not a number"}
+			}
+			return v, false, nil
+		}
+		v = v.Elem()
+	}
+	return v, true, nil
+}
+
+// elementError adds the index of the offending element to err.
+func elementError(err error, i int) error {
+	if e, ok := err.(*Error); ok {
+		e.Msg = fmt.Sprintf("element %d: %s", i, e.Msg)
+	}
+	return err
+}
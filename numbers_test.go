@@ -0,0 +1,78 @@
+package generics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type cents int
+
+func TestNumbers(t *testing.T) {
+	one, two := 1, 2
+	tests := []struct {
+		In   interface{}
+		Want string
+	}{
+		{[]int64{1, 2, 3}, "[1 2 3]"},
+		{[]uint32{1, 2}, "[1 2]"},
+		{[]float32{1.5, 2}, "[1.5 2]"},
+		{[]int8{-1, 1}, "[-1 1]"},
+		{[]cents{100, 250}, "[100 250]"},
+		{[]*int{&one, nil, &two}, "[1 2]"},
+		{[]json.Number{"1.5", "2"}, "[1.5 2]"},
+		{[]interface{}{1, uint(2), 3.5, nil, cents(4)}, "[1 2 3.5 4]"},
+	}
+	for i, tc := range tests {
+		res, err := Numbers(tc.In)
+		if err != nil {
+			t.Errorf("%d: %v", i+1, err)
+			continue
+		}
+		if has := fmt.Sprint(res); has != tc.Want {
+			t.Errorf("%d: want %s, was %s", i+1, tc.Want, has)
+		}
+	}
+
+	if sum := Sum([]cents{100, 250}); sum != 350 {
+		t.Errorf("expected 350, was %v", sum)
+	}
+	if max := Max([]*int{nil, &two, &one}); max != 2 {
+		t.Errorf("expected 2, was %v", max)
+	}
+}
+
+func TestNumbersOptions(t *testing.T) {
+	one := 1
+	res, err := Numbers([]string{"1", "2.5"}, ParseStrings())
+	if has := fmt.Sprint(res); has != "[1 2.5]" || err != nil {
+		t.Errorf("was %s, %v", has, err)
+	}
+
+	tests := []struct {
+		In   interface{}
+		Opts []NumberOption
+		Want string
+	}{
+		{[]string{"1"}, nil, `generics: unsupported type: element 0: expected number, was string`},
+		{[]string{"1", "x"}, []NumberOption{ParseStrings()}, `generics: unsupported type: element 1: "x" is not a number`},
+		{[]*int{&one, nil}, []NumberOption{NilAsError()}, `generics: unsupported type: element 1: nil is not a number`},
+		{[]interface{}{1, true}, nil, `generics: unsupported type: element 1: expected number, was bool`},
+	}
+	for i, tc := range tests {
+		_, err := Numbers(tc.In, tc.Opts...)
+		if !errors.Is(err, ErrUnsupportedType) || err.Error() != tc.Want {
+			t.Errorf("%d: want %s, was %v", i+1, tc.Want, err)
+		}
+	}
+}
+
+func TestNonNumericPanics(t *testing.T) {
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("expected ErrUnsupportedType, was %v", err)
+		}
+	}()
+	Sum([]string{"1"})
+}
@@ -0,0 +1,299 @@
+package generics
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// ParallelMap is like Map but calls fn from up to limit goroutines at once
+// (GOMAXPROCS when limit is not positive). fn may be an attribute name or a
+// func with one of the signatures
+//
+//	func(T) U
+//	func(T) (U, error)
+//	func(context.Context, T) U
+//	func(context.Context, T) (U, error)
+//
+// The result keeps the order of list. The first error, panic or the
+// cancellation of ctx stops all workers and is returned.
+func ParallelMap(ctx context.Context, list interface{}, fn interface{}, limit int) (res interface{}, err error) {
+	defer catch(&err)
+	v, err := sliceValue(list)
+	if err != nil {
+		return nil, err
+	}
+	tp, call, err := parallelFunc(v.Type().Elem(), fn)
+	if err != nil {
+		return nil, err
+	}
+	out := reflect.MakeSlice(reflect.SliceOf(tp), v.Len(), v.Len())
+	err = parallel(ctx, v.Len(), limit, func(ctx context.Context, i int) error {
+		res, err := call(ctx, v.Index(i))
+		if err != nil {
+			return err
+		}
+		out.Index(i).Set(res)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Interface(), nil
+}
+
+// ParallelSelect is like Select but runs filter like ParallelMap runs fn.
+func ParallelSelect(ctx context.Context, list interface{}, filter interface{}, limit int) (res interface{}, err error) {
+	defer catch(&err)
+	v, err := sliceValue(list)
+	if err != nil {
+		return nil, err
+	}
+	tp, call, err := parallelFunc(v.Type().Elem(), filter)
+	if err != nil {
+		return nil, err
+	}
+	if tp.Kind() != reflect.Bool {
+		return nil, badSignature(reflect.TypeOf(filter), "expected filter to return bool")
+	}
+	keep := make([]bool, v.Len())
+	err = parallel(ctx, v.Len(), limit, func(ctx context.Context, i int) error {
+		res, err := call(ctx, v.Index(i))
+		keep[i] = err == nil && res.Bool()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	n := 0
+	for _, k := range keep {
+		if k {
+			n++
+		}
+	}
+	out := reflect.MakeSlice(resultSliceType(v), 0, n)
+	for i, k := range keep {
+		if k {
+			out = reflect.Append(out, v.Index(i))
+		}
+	}
+	return out.Interface(), nil
+}
+
+// ParallelReduce combines all elements of list with combine, a func(T, T) T
+// or func(T, T) (T, error), which must be associative. list is split into one
+// chunk per worker, each chunk is reduced concurrently and the results are
+// combined in order. An empty list reduces to the zero value of T.
+func ParallelReduce(ctx context.Context, list interface{}, combine interface{}, limit int) (res interface{}, err error) {
+	defer catch(&err)
+	v, err := sliceValue(list)
+	if err != nil {
+		return nil, err
+	}
+	el := v.Type().Elem()
+	ft := reflect.TypeOf(combine)
+	if ft == nil || ft.Kind() != reflect.Func || ft.NumIn() != 2 || !el.AssignableTo(ft.In(0)) || !el.AssignableTo(ft.In(1)) ||
+		ft.NumOut() < 1 || ft.NumOut() > 2 || ft.Out(0) != el || (ft.NumOut() == 2 && ft.Out(1) != errorType) {
+		return nil, badSignature(ft, fmt.Sprintf("expected func(%v, %v) %v", el, el, el))
+	}
+	fv := reflect.ValueOf(combine)
+	reduce := func(acc, x reflect.Value) (reflect.Value, error) {
+		out := fv.Call([]reflect.Value{acc, x})
+		if len(out) == 2 && !out[1].IsNil() {
+			return acc, out[1].Interface().(error)
+		}
+		return out[0], nil
+	}
+
+	if v.Len() == 0 {
+		return reflect.Zero(el).Interface(), nil
+	}
+	chunks := workers(limit, v.Len())
+	size := (v.Len() + chunks - 1) / chunks
+	chunks = (v.Len() + size - 1) / size
+	partial := make([]reflect.Value, chunks)
+	err = parallel(ctx, chunks, limit, func(ctx context.Context, c int) error {
+		acc := v.Index(c * size)
+		for i := c*size + 1; i < v.Len() && i < (c+1)*size; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			var err error
+			if acc, err = reduce(acc, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		partial[c] = acc
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the partial results are combined under the same recovery as the chunks
+	acc := partial[0]
+	err = safeWork(ctx, 0, func(context.Context, int) error {
+		for _, p := range partial[1:] {
+			var err error
+			if acc, err = reduce(acc, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return acc.Interface(), nil
+}
+
+// parallelFunc wraps fn, see ParallelMap, into a call returning its result
+// and error.
+func parallelFunc(el reflect.Type, fn interface{}) (reflect.Type, func(ctx context.Context, v reflect.Value) (reflect.Value, error), error) {
+	ft := reflect.TypeOf(fn)
+	if ft == nil || ft.Kind() != reflect.Func {
+		tp, getter, err := newGetter(el, fn)
+		if err != nil {
+			return nil, nil, err
+		}
+		return tp, func(_ context.Context, v reflect.Value) (reflect.Value, error) {
+			return getter(v), nil
+		}, nil
+	}
+	withCtx := ft.NumIn() == 2 && ft.In(0) == contextType
+	withErr := ft.NumOut() == 2 && ft.Out(1) == errorType
+	if (ft.NumIn() != 1 && !withCtx) || !el.AssignableTo(ft.In(ft.NumIn()-1)) || (ft.NumOut() != 1 && !withErr) {
+		return nil, nil, badSignature(ft, fmt.Sprintf("expected func([context.Context, ]%v) (U[, error])", el))
+	}
+	fv := reflect.ValueOf(fn)
+	return ft.Out(0), func(ctx context.Context, v reflect.Value) (reflect.Value, error) {
+		args := []reflect.Value{v}
+		if withCtx {
+			args = []reflect.Value{reflect.ValueOf(&ctx).Elem(), v}
+		}
+		out := fv.Call(args)
+		if withErr && !out[1].IsNil() {
+			return out[0], out[1].Interface().(error)
+		}
+		return out[0], nil
+	}, nil
+}
+
+func workers(limit, n int) int {
+	if limit <= 0 {
+		limit = runtime.GOMAXPROCS(0)
+	}
+	if limit > n {
+		return n
+	}
+	return limit
+}
+
+// parallel calls work for all indexes below n from up to limit goroutines.
+// It stops at the first error, which is returned, or when ctx is done.
+// Panics in work are returned as ErrPanic.
+func parallel(parent context.Context, n, limit int, work func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	var (
+		once     sync.Once
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	indexes := make(chan int)
+	for w := 0; w < workers(limit, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := safeWork(ctx, i, work); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return parent.Err()
+}
+
+func safeWork(ctx context.Context, i int, work func(ctx context.Context, i int) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(*Error); ok {
+				err = e
+				return
+			}
+			err = &Error{Kind: ErrPanic, Msg: fmt.Sprintf("%v", r)}
+		}
+	}()
+	return work(ctx, i)
+}
+
+// ParallelCollection runs Map, Select and Reduce of a Collection
+// concurrently, see Collection.Parallel.
+type ParallelCollection struct {
+	c     *Collection
+	ctx   context.Context
+	limit int
+}
+
+// Parallel returns a view of the collection running its operations from up
+// to limit goroutines:
+//
+//	names, err := New(list).Parallel(8).WithContext(ctx).Map(lookup)
+func (c *Collection) Parallel(limit int) *ParallelCollection {
+	return &ParallelCollection{c: c, ctx: context.Background(), limit: limit}
+}
+
+func (p *ParallelCollection) WithContext(ctx context.Context) *ParallelCollection {
+	return &ParallelCollection{c: p.c, ctx: ctx, limit: p.limit}
+}
+
+func (p *ParallelCollection) Map(fn interface{}) (*Collection, error) {
+	res, err := ParallelMap(p.ctx, p.c.collection, fn, p.limit)
+	if err != nil {
+		return nil, err
+	}
+	return New(res), nil
+}
+
+func (p *ParallelCollection) Select(fn interface{}) (*Collection, error) {
+	res, err := ParallelSelect(p.ctx, p.c.collection, fn, p.limit)
+	if err != nil {
+		return nil, err
+	}
+	return New(res), nil
+}
+
+func (p *ParallelCollection) Reduce(combine interface{}) (interface{}, error) {
+	return ParallelReduce(p.ctx, p.c.collection, combine, p.limit)
+}
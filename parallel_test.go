@@ -0,0 +1,144 @@
+package generics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelMap(t *testing.T) {
+	ints := make([]int, 100)
+	for i := range ints {
+		ints[i] = i
+	}
+	var running, max int32
+	square := func(ctx context.Context, v int) (int, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return v * v, nil
+	}
+
+	res, err := ParallelMap(context.Background(), ints, square, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	squares := res.([]int)
+	for i, v := range squares {
+		if v != i*i {
+			t.Fatalf("%d: want %d, was %d", i, i*i, v)
+		}
+	}
+	if max > 4 {
+		t.Errorf("expected at most 4 concurrent calls, was %d", max)
+	}
+
+	names, err := ParallelMap(context.Background(), []*record{{Name: "a"}, {Name: "b"}}, "Name", 0)
+	if has := fmt.Sprint(names); has != "[a b]" || err != nil {
+		t.Errorf("was %s, %v", has, err)
+	}
+}
+
+func TestParallelErrors(t *testing.T) {
+	ints := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	failure := errors.New("failure")
+	var calls int32
+	failing := func(v int) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		if v == 2 {
+			return 0, failure
+		}
+		time.Sleep(10 * time.Millisecond)
+		return v, nil
+	}
+	if _, err := ParallelMap(context.Background(), ints, failing, 1); !errors.Is(err, failure) {
+		t.Errorf("expected failure, was %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected to stop after 2 calls, was %d", calls)
+	}
+
+	panicking := func(v int) bool { panic("boom") }
+	_, err := ParallelSelect(context.Background(), ints, panicking, 2)
+	if !errors.Is(err, ErrPanic) || err.Error() != "generics: panic in worker: boom" {
+		t.Errorf("expected ErrPanic, was %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ParallelMap(ctx, ints, func(v int) int { return v }, 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, was %v", err)
+	}
+
+	if _, err := ParallelMap(context.Background(), ints, func(v string) int { return 0 }, 2); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("expected ErrBadSignature, was %v", err)
+	}
+}
+
+func TestParallelSelectAndReduce(t *testing.T) {
+	ints := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	even, err := ParallelSelect(context.Background(), ints, func(v int) bool { return v%2 == 0 }, 3)
+	if has := fmt.Sprint(even); has != "[2 4 6 8 10]" || err != nil {
+		t.Errorf("was %s, %v", has, err)
+	}
+
+	for _, limit := range []int{0, 1, 3, 20} {
+		sum, err := ParallelReduce(context.Background(), ints, func(a, b int) int { return a + b }, limit)
+		if sum != 55 || err != nil {
+			t.Errorf("%d: was %v, %v", limit, sum, err)
+		}
+	}
+
+	// not commutative, so the chunks must be combined in order
+	words := []string{"a", "b", "c", "d", "e"}
+	joined, _ := ParallelReduce(context.Background(), words, func(a, b string) string { return a + b }, 2)
+	if joined != "abcde" {
+		t.Errorf("was %v", joined)
+	}
+
+	empty, err := ParallelReduce(context.Background(), []int{}, func(a, b int) int { return a + b }, 2)
+	if empty != 0 || err != nil {
+		t.Errorf("was %v, %v", empty, err)
+	}
+
+	// the chunks reduce to 3 and 7, only combining them panics
+	_, err = ParallelReduce(context.Background(), []int{1, 2, 3, 4}, func(a, b int) int {
+		if a+b == 10 {
+			panic("boom")
+		}
+		return a + b
+	}, 2)
+	if !errors.Is(err, ErrPanic) {
+		t.Errorf("expected ErrPanic, was %v", err)
+	}
+
+	odd, err := ParallelSelect(context.Background(), [4]int{1, 2, 3, 4}, func(v int) bool { return v%2 == 1 }, 2)
+	if has := fmt.Sprintf("%#v", odd); has != "[]int{1, 3}" || err != nil {
+		t.Errorf("was %s, %v", has, err)
+	}
+}
+
+func TestCollectionParallel(t *testing.T) {
+	list := []*record{{Name: "one", Amount: 1}, {Name: "two", Amount: 2}, {Name: "three", Amount: 3}}
+	c, err := New(list).Parallel(2).WithContext(context.Background()).Select(func(r *record) bool { return r.Amount > 1 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, err := c.Parallel(2).Map("Name")
+	if has := fmt.Sprint(names.Cast()); has != "[two three]" || err != nil {
+		t.Errorf("was %s, %v", has, err)
+	}
+	sum, err := New([]int{1, 2, 3}).Parallel(2).Reduce(func(a, b int) int { return a + b })
+	if sum != 6 || err != nil {
+		t.Errorf("was %v, %v", sum, err)
+	}
+}
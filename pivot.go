@@ -0,0 +1,218 @@
+package generics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Aggregator reduces the elements of one pivot cell, a slice of the list's
+// element type, to a number.
+type Aggregator func(cell interface{}) float64
+
+// CountOf counts the elements of a cell.
+func CountOf(cell interface{}) float64 {
+	return float64(reflect.ValueOf(cell).Len())
+}
+
+// AggregateOf applies stat, one of the statistics functions like Sum,
+// Average or Median, to key of the elements of a cell:
+//
+//	Pivot(payments, "AccountID", "Month", AggregateOf(Sum, "Amount"))
+func AggregateOf(stat func(in interface{}) float64, key interface{}) Aggregator {
+	return func(cell interface{}) float64 {
+		return stat(Map(cell, key))
+	}
+}
+
+// PivotOption changes which totals a PivotTable renders.
+type PivotOption func(*PivotTable)
+
+// RowTotals adds a column with the total of every row.
+func RowTotals() PivotOption {
+	return func(t *PivotTable) { t.rowTotals = true }
+}
+
+// ColumnTotals adds a row with the total of every column.
+func ColumnTotals() PivotOption {
+	return func(t *PivotTable) { t.columnTotals = true }
+}
+
+// PivotTable is a cross tabulation of a list, see Pivot. Rows and Columns
+// are the sorted row and column keys as a []K of the key type. Cells are
+// indexed by row, then column. Totals apply the aggregator to all elements
+// of a row or column, so they are correct for averages too, and are always
+// computed but only rendered when requested.
+type PivotTable struct {
+	Rows         interface{}
+	Columns      interface{}
+	Cells        [][]float64
+	RowTotals    []float64
+	ColumnTotals []float64
+	Total        float64
+
+	rowIndex     map[interface{}]int
+	columnIndex  map[interface{}]int
+	rowTotals    bool
+	columnTotals bool
+}
+
+// Pivot groups list by rowKey and colKey, attributes or funcs as accepted by
+// Group, and aggregates every cell with agg. Rows and columns are in key
+// order as by GroupBy. Cells without elements are aggregated as an empty
+// slice, so they are 0 for sums and counts and NaN for averages.
+//
+//	t := Pivot(payments, "Account.Name", "Month", AggregateOf(Sum, "Amount"), RowTotals(), ColumnTotals())
+//	fmt.Print(t)
+//	err := t.WriteCSV(w)
+func Pivot(list, rowKey, colKey interface{}, agg Aggregator, opts ...PivotOption) *PivotTable {
+	return must(PivotE(list, rowKey, colKey, agg, opts...)).(*PivotTable)
+}
+
+func PivotE(list, rowKey, colKey interface{}, agg Aggregator, opts ...PivotOption) (res *PivotTable, err error) {
+	defer catch(&err)
+	if _, err := sliceValue(list); err != nil {
+		return nil, err
+	}
+	if agg == nil {
+		return nil, &Error{Kind: ErrBadSignature, Msg: "expected an aggregator"}
+	}
+	c := New(list)
+	rows := c.GroupBy(rowKey)
+	cols := c.GroupBy(colKey)
+
+	t := &PivotTable{
+		Rows:         headers(rows),
+		Columns:      headers(cols),
+		Cells:        make([][]float64, rows.Len()),
+		RowTotals:    make([]float64, rows.Len()),
+		ColumnTotals: make([]float64, cols.Len()),
+		Total:        agg(list),
+		rowIndex:     keyIndex(rows),
+		columnIndex:  keyIndex(cols),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	empty := reflect.MakeSlice(reflect.SliceOf(rows.elemType), 0, 0).Interface()
+	for i, group := range rows.groups {
+		t.RowTotals[i] = agg(group.Interface())
+		cells := New(group.Interface()).GroupBy(colKey)
+		byColumn := map[interface{}]interface{}{}
+		for j, k := range cells.keys {
+			byColumn[k.Interface()] = cells.groups[j].Interface()
+		}
+		t.Cells[i] = make([]float64, cols.Len())
+		for j, k := range cols.keys {
+			cell, ok := byColumn[k.Interface()]
+			if !ok {
+				cell = empty
+			}
+			t.Cells[i][j] = agg(cell)
+		}
+	}
+	for j, group := range cols.groups {
+		t.ColumnTotals[j] = agg(group.Interface())
+	}
+	return t, nil
+}
+
+func headers(g *GroupedCollection) interface{} {
+	out := reflect.MakeSlice(reflect.SliceOf(g.keyType), len(g.keys), len(g.keys))
+	for i, k := range g.keys {
+		out.Index(i).Set(k)
+	}
+	return out.Interface()
+}
+
+func keyIndex(g *GroupedCollection) map[interface{}]int {
+	index := make(map[interface{}]int, len(g.keys))
+	for i, k := range g.keys {
+		index[k.Interface()] = i
+	}
+	return index
+}
+
+// Cell returns the aggregate for the given row and column key, or NaN if
+// either key does not occur in the list.
+func (t *PivotTable) Cell(row, col interface{}) float64 {
+	i, ok := t.rowIndex[row]
+	j, ok2 := t.columnIndex[col]
+	if !ok || !ok2 {
+		return math.NaN()
+	}
+	return t.Cells[i][j]
+}
+
+// Records returns the table as rendered, with a header row and the row keys
+// in the first column. NaN cells are empty.
+func (t *PivotTable) Records() [][]string {
+	rows, cols := reflect.ValueOf(t.Rows), reflect.ValueOf(t.Columns)
+	header := []string{""}
+	for j := 0; j < cols.Len(); j++ {
+		header = append(header, fmt.Sprint(cols.Index(j).Interface()))
+	}
+	if t.rowTotals {
+		header = append(header, "Total")
+	}
+	records := [][]string{header}
+	for i := 0; i < rows.Len(); i++ {
+		record := []string{fmt.Sprint(rows.Index(i).Interface())}
+		for _, v := range t.Cells[i] {
+			record = append(record, formatCell(v))
+		}
+		if t.rowTotals {
+			record = append(record, formatCell(t.RowTotals[i]))
+		}
+		records = append(records, record)
+	}
+	if t.columnTotals {
+		record := []string{"Total"}
+		for _, v := range t.ColumnTotals {
+			record = append(record, formatCell(v))
+		}
+		if t.rowTotals {
+			record = append(record, formatCell(t.Total))
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func formatCell(v float64) string {
+	if math.IsNaN(v) {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// WriteCSV writes the records of the table as CSV.
+func (t *PivotTable) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(t.Records()); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// WriteText writes the records of the table as right aligned text columns.
+func (t *PivotTable) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	for _, record := range t.Records() {
+		if _, err := fmt.Fprintln(tw, strings.Join(record, "\t")+"\t"); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+func (t *PivotTable) String() string {
+	var b strings.Builder
+	_ = t.WriteText(&b)
+	return b.String()
+}
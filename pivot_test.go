@@ -0,0 +1,104 @@
+package generics
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestPivot(t *testing.T) {
+	sum := Pivot(groupedPayments(), "AccountID", "Currency", AggregateOf(Sum, "Amount"), RowTotals(), ColumnTotals())
+	avg := New(groupedPayments()).Pivot("Currency", "AccountID", AggregateOf(Average, "Amount"), ColumnTotals())
+	count := Pivot(groupedPayments(), "AccountID", "Currency", CountOf)
+
+	tests := []struct{ Has, Want interface{} }{
+		{len(sum.Rows.([]int)), 3},
+		{sum.Columns.([]string)[1], "USD"},
+		{sum.Cell(1, "EUR"), 21.0},
+		{sum.Cell(3, "USD"), 0.0},
+		{math.IsNaN(sum.Cell(4, "EUR")), true},
+		{sum.RowTotals[0], 51.0},
+		{sum.ColumnTotals[0], 71.0},
+		{sum.Total, 106.0},
+		{avg.Cell("EUR", 3), 25.0},
+		{math.IsNaN(avg.Cell("USD", 3)), true},
+		{avg.ColumnTotals[0], 17.0},
+		{count.Cell(1, "EUR"), 2.0},
+		{count.Total, 6.0},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestPivotRender(t *testing.T) {
+	sum := Pivot(groupedPayments(), "AccountID", "Currency", AggregateOf(Sum, "Amount"), RowTotals(), ColumnTotals())
+	avg := Pivot(groupedPayments(), "Currency", "AccountID", AggregateOf(Average, "Amount"))
+
+	var csv bytes.Buffer
+	if err := sum.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	var avgCSV bytes.Buffer
+	if err := avg.WriteCSV(&avgCSV); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct{ Has, Want interface{} }{
+		{csv.String(), ",EUR,USD,Total\n1,21,30,51\n2,0,5,5\n3,50,0,50\nTotal,71,35,106\n"},
+		{avgCSV.String(), ",1,2,3\nEUR,10.5,,25\nUSD,30,5,\n"},
+		{sum.String(), "" +
+			"         EUR  USD  Total\n" +
+			"      1   21   30     51\n" +
+			"      2    0    5      5\n" +
+			"      3   50    0     50\n" +
+			"  Total   71   35    106\n"},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestPivotErrors(t *testing.T) {
+	_, errList := PivotE(1, "AccountID", "Currency", CountOf)
+	_, errKey := PivotE(groupedPayments(), "Missing", "Currency", CountOf)
+	_, errAgg := PivotE(groupedPayments(), "AccountID", "Currency", nil)
+
+	tests := []struct{ Has, Want interface{} }{
+		{errors.Is(errList, ErrUnsupportedType), true},
+		{errors.Is(errKey, ErrFieldNotFound), true},
+		{errors.Is(errAgg, ErrBadSignature), true},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestPivotPointerKeys(t *testing.T) {
+	b, a := &Account{ID: 2, Name: "b"}, &Account{ID: 1, Name: "a"}
+	list := []*Payment{{ID: 1, Account: b}, {ID: 2, Account: a}, {ID: 3, Account: b}}
+	p, err := PivotE(list, "Account", "ID", CountOf, RowTotals())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct{ Has, Want interface{} }{
+		{fmt.Sprint(Map(p.Rows, "Name")), "[b a]"},
+		{fmt.Sprint(p.Columns), "[1 2 3]"},
+		{fmt.Sprint(p.RowTotals), "[2 1]"},
+		{p.Cell(b, 3), 1.0},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package generics
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// PreloadOption changes how Preload calls its loader.
+type PreloadOption func(*preloadOptions)
+
+type preloadOptions struct {
+	batchSize      int
+	concurrency    int
+	foreignKeyName string
+	primaryKeyName string
+}
+
+// BatchSize passes at most n keys to every loader call. By default all keys
+// are loaded in one call.
+func BatchSize(n int) PreloadOption {
+	return func(o *preloadOptions) { o.batchSize = n }
+}
+
+// Concurrency runs up to n loader calls at once, GOMAXPROCS when n is not
+// positive. By default batches are loaded one after another.
+func Concurrency(n int) PreloadOption {
+	return func(o *preloadOptions) { o.concurrency = n }
+}
+
+// PreloadKeys sets the foreign key on the collection and the primary key on
+// the loaded elements, comma separated for composite keys.
+func PreloadKeys(foreignKeyName, primaryKeyName string) PreloadOption {
+	return func(o *preloadOptions) {
+		o.foreignKeyName, o.primaryKeyName = foreignKeyName, primaryKeyName
+	}
+}
+
+// PreloadReport describes a finished Preload. Missing holds the foreign keys,
+// as a []K, for which the loader returned no element. NullKeys counts the
+// elements with a nil or NULL foreign key, which are not loaded.
+type PreloadReport struct {
+	Keys     int
+	Batches  int
+	Loaded   int
+	Missing  interface{}
+	NullKeys int
+}
+
+// Preload loads the elements the collection belongs to through loader and
+// sets them on the field name, like Join does with elements already loaded.
+// loader is a func(context.Context, []K) ([]V, error) called with the
+// distinct foreign keys of the collection, converted to K as Join does, in
+// order of first occurrence:
+//
+//	report, err := Preload(ctx, payments, "Account", func(ctx context.Context, ids []int) ([]*Account, error) {
+//		return repo.FindAccounts(ctx, ids)
+//	}, BatchSize(500), Concurrency(4))
+//
+// The keys are taken from a belongs_to tag on name or else by convention:
+// name plus "ID" on the collection and "ID" on V, see PreloadKeys to
+// override them. Elements whose key the loader did not return are set to the
+// zero value and reported in Missing. The first loader error is returned and
+// nothing is set.
+func Preload(ctx context.Context, collection interface{}, name string, loader interface{}, opts ...PreloadOption) (report *PreloadReport, err error) {
+	defer catch(&err)
+	sv, err := sliceValue(collection)
+	if err != nil {
+		return nil, err
+	}
+	el := sv.Type().Elem()
+	if derefType(el).Kind() != reflect.Struct {
+		return nil, unsupportedType(sv.Type(), "expected collection of structs")
+	}
+	o := &preloadOptions{concurrency: 1, foreignKeyName: name + "ID", primaryKeyName: "ID"}
+	if err := o.fromTag(el, name); err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	foreignKeys, primaryKeys, err := keyPair(sv.Type(), o.foreignKeyName, o.primaryKeyName)
+	if err != nil {
+		return nil, err
+	}
+
+	lt := reflect.TypeOf(loader)
+	if lt == nil || lt.Kind() != reflect.Func || lt.NumIn() != 2 || lt.In(0) != contextType || lt.In(1).Kind() != reflect.Slice ||
+		lt.NumOut() != 2 || lt.Out(0).Kind() != reflect.Slice || lt.Out(1) != errorType {
+		return nil, badSignature(lt, "expected func(context.Context, []K) ([]V, error)")
+	}
+	keyType, valueType := lt.In(1).Elem(), lt.Out(0).Elem()
+	if !keyType.Comparable() {
+		return nil, unsupportedType(keyType, "expected comparable key")
+	}
+	target, ok := derefType(el).FieldByName(name)
+	if !ok {
+		return nil, fieldNotFound(derefType(el), name)
+	}
+	if !valueType.AssignableTo(target.Type) {
+		return nil, &Error{Kind: ErrUnsupportedType, Type: target.Type, Field: name,
+			Msg: fmt.Sprintf("cannot assign %v to %s of type %v", valueType, name, target.Type)}
+	}
+	foreignKey, err := matchingKeyGetter(el, foreignKeys, keyType)
+	if err != nil {
+		return nil, err
+	}
+	primaryKey, err := matchingKeyGetter(valueType, primaryKeys, keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := reflect.MakeSlice(reflect.SliceOf(keyType), 0, sv.Len())
+	seen := map[interface{}]bool{}
+	for i := 0; i < sv.Len(); i++ {
+		k, _ := foreignKey(sv.Index(i))
+		if k.IsValid() && !seen[k.Interface()] {
+			seen[k.Interface()] = true
+			keys = reflect.Append(keys, k)
+		}
+	}
+
+	size := o.batchSize
+	if size <= 0 || size > keys.Len() {
+		size = keys.Len()
+	}
+	batches := 0
+	if size > 0 {
+		batches = (keys.Len() + size - 1) / size
+	}
+	results := make([]reflect.Value, batches)
+	fv := reflect.ValueOf(loader)
+	err = parallel(ctx, batches, o.concurrency, func(ctx context.Context, b int) error {
+		batch := keys.Slice(b*size, clamp((b+1)*size, keys.Len()))
+		out := fv.Call([]reflect.Value{reflect.ValueOf(&ctx).Elem(), batch})
+		if !out[1].IsNil() {
+			return out[1].Interface().(error)
+		}
+		results[b] = out[0]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m := reflect.MakeMap(reflect.MapOf(keyType, valueType))
+	for _, res := range results {
+		for i := 0; i < res.Len(); i++ {
+			if k, _ := primaryKey(res.Index(i)); k.IsValid() {
+				m.SetMapIndex(k, res.Index(i))
+			}
+		}
+	}
+	join, err := setFromMap(sv, m, name, foreignKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := reflect.MakeSlice(keys.Type(), 0, 0)
+	for i := 0; i < keys.Len(); i++ {
+		if !m.MapIndex(keys.Index(i)).IsValid() {
+			missing = reflect.Append(missing, keys.Index(i))
+		}
+	}
+	return &PreloadReport{Keys: keys.Len(), Batches: batches, Loaded: m.Len(), Missing: missing.Interface(), NullKeys: len(join.NullKeys)}, nil
+}
+
+// fromTag takes the keys from a belongs_to tag on the field name of el.
+func (o *preloadOptions) fromTag(el reflect.Type, name string) error {
+	assocs, err := Associations(el)
+	if err != nil {
+		return err
+	}
+	for _, a := range assocs {
+		if a.Field == name && a.Kind == BelongsTo {
+			o.foreignKeyName, o.primaryKeyName = a.ForeignKey, a.PrimaryKey
+		}
+	}
+	return nil
+}
+
+func (c *Collection) Preload(ctx context.Context, name string, loader interface{}, opts ...PreloadOption) (*PreloadReport, error) {
+	return Preload(ctx, c.collection, name, loader, opts...)
+}
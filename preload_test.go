@@ -0,0 +1,160 @@
+package generics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+)
+
+type accountRepo struct {
+	mu       sync.Mutex
+	accounts map[int]*Account
+	calls    [][]int
+}
+
+func (r *accountRepo) load(ctx context.Context, ids []int) ([]*Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, append([]int(nil), ids...))
+	var res []*Account
+	for _, id := range ids {
+		if a, ok := r.accounts[id]; ok {
+			res = append(res, a)
+		}
+	}
+	return res, nil
+}
+
+func newAccountRepo() *accountRepo {
+	return &accountRepo{accounts: map[int]*Account{1: {1, "one"}, 2: {2, "two"}, 3: {3, "three"}}}
+}
+
+func preloadPayments() []*Payment {
+	return []*Payment{{ID: 1, AccountID: 2}, {ID: 2, AccountID: 1}, {ID: 3, AccountID: 2}, {ID: 4, AccountID: 9}, {ID: 5, AccountID: 3}}
+}
+
+func TestPreload(t *testing.T) {
+	ctx := context.Background()
+	repo := newAccountRepo()
+	payments := preloadPayments()
+	report, err := Preload(ctx, payments, "Account", repo.load)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batched := newAccountRepo()
+	_, err = New(preloadPayments()).Preload(ctx, "Account", batched.load, BatchSize(2), Concurrency(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(batched.calls, func(a, b int) bool { return batched.calls[a][0] < batched.calls[b][0] })
+
+	tests := []struct{ Has, Want interface{} }{
+		{payments[0].Account.Name, "two"},
+		{payments[1].Account.Name, "one"},
+		{payments[2].Account == payments[0].Account, true},
+		{payments[3].Account == nil, true},
+		{fmt.Sprint(repo.calls), "[[2 1 9 3]]"},
+		{fmt.Sprint(report.Missing), "[9]"},
+		{fmt.Sprint(report.Keys, report.Batches, report.Loaded), "4 1 3"},
+		{fmt.Sprint(batched.calls), "[[2 1] [9 3]]"},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestPreloadKeys(t *testing.T) {
+	type owned struct {
+		OwnerID string
+		Owner   *Account
+	}
+	type tagged struct {
+		CreatorID int
+		Creator   *Account `generics:"belongs_to"`
+	}
+	byName := func(ctx context.Context, names []string) ([]*Account, error) {
+		return []*Account{{1, "one"}}, nil
+	}
+	list := []*owned{{"one", nil}, {"two", nil}}
+	report, err := Preload(context.Background(), list, "Owner", byName, PreloadKeys("OwnerID", "Name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	creators := []*tagged{{CreatorID: 3}}
+	if _, err := Preload(context.Background(), creators, "Creator", newAccountRepo().load); err != nil {
+		t.Fatal(err)
+	}
+	empty, err := Preload(context.Background(), []*tagged{}, "Creator", newAccountRepo().load)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type optional struct {
+		AccountID *int64
+		Account   *Account
+	}
+	two := int64(2)
+	optionals := []*optional{{AccountID: &two}, {}}
+	nulls, err := Preload(context.Background(), optionals, "Account", newAccountRepo().load)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct{ Has, Want interface{} }{
+		{list[0].Owner.ID, 1},
+		{list[1].Owner == nil, true},
+		{fmt.Sprint(report.Missing), "[two]"},
+		{creators[0].Creator.Name, "three"},
+		{empty.Batches, 0},
+		{optionals[0].Account.Name, "two"},
+		{optionals[1].Account == nil, true},
+		{fmt.Sprint(nulls.Keys, nulls.NullKeys), "1 1"},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestPreloadErrors(t *testing.T) {
+	ctx := context.Background()
+	failed := errors.New("failed")
+	payments := preloadPayments()
+	_, errLoader := Preload(ctx, payments, "Account", func(ctx context.Context, ids []int) ([]*Account, error) {
+		return nil, failed
+	}, BatchSize(1), Concurrency(3))
+	_, errSignature := Preload(ctx, payments, "Account", func(ids []int) ([]*Account, error) { return nil, nil })
+	_, errKeyType := Preload(ctx, payments, "Account", func(ctx context.Context, ids []string) ([]*Account, error) { return nil, nil })
+	_, errField := Preload(ctx, payments, "Missing", newAccountRepo().load, PreloadKeys("AccountID", "ID"))
+	_, errTarget := Preload(ctx, payments, "ID", newAccountRepo().load, PreloadKeys("AccountID", "ID"))
+	ctxDone, cancel := context.WithCancel(ctx)
+	cancel()
+	_, errCtx := Preload(ctxDone, payments, "Account", newAccountRepo().load)
+
+	tests := []struct {
+		Err  error
+		Want error
+	}{
+		{errLoader, failed},
+		{errSignature, ErrBadSignature},
+		{errKeyType, ErrUnsupportedType},
+		{errField, ErrFieldNotFound},
+		{errTarget, ErrUnsupportedType},
+		{errCtx, context.Canceled},
+	}
+	for i, tc := range tests {
+		if !errors.Is(tc.Err, tc.Want) {
+			t.Errorf("%d: want %v, was %v", i+1, tc.Want, tc.Err)
+		}
+	}
+	if payments[0].Account != nil {
+		t.Errorf("want no account after errors, was %v", payments[0].Account)
+	}
+}
@@ -0,0 +1,190 @@
+package generics
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// InnerJoin pairs every element of left with every element of right with an
+// equal key. leftKey and rightKey are attributes or funcs as accepted by
+// Map. Keys are compared without pointers and sql.Null* wrappers, left keys
+// converted to the right key type like Join does. Nil and NULL keys on
+// either side match nothing. The result is a slice of pairs typed after the
+// two element types, in the order of left, then of right:
+//
+//	pairs := InnerJoin(payments, accounts, "AccountID", "ID").([]struct {
+//		Left  *Payment
+//		Right *Account
+//	})
+//
+// Use InnerJoinWith to combine the pairs into anything else.
+func InnerJoin(left, right, leftKey, rightKey interface{}) interface{} {
+	return must(InnerJoinE(left, right, leftKey, rightKey))
+}
+
+func InnerJoinE(left, right, leftKey, rightKey interface{}) (interface{}, error) {
+	return relationalJoin(left, right, leftKey, rightKey, nil, false, false)
+}
+
+// LeftJoin joins like InnerJoin and keeps elements of left without a match,
+// paired with the zero value of the right element type. Join slices of
+// pointers to tell those apart from matches with zero values.
+func LeftJoin(left, right, leftKey, rightKey interface{}) interface{} {
+	return must(LeftJoinE(left, right, leftKey, rightKey))
+}
+
+func LeftJoinE(left, right, leftKey, rightKey interface{}) (interface{}, error) {
+	return relationalJoin(left, right, leftKey, rightKey, nil, true, false)
+}
+
+// RightJoin joins like InnerJoin and keeps elements of right without a
+// match, after all matches.
+func RightJoin(left, right, leftKey, rightKey interface{}) interface{} {
+	return must(RightJoinE(left, right, leftKey, rightKey))
+}
+
+func RightJoinE(left, right, leftKey, rightKey interface{}) (interface{}, error) {
+	return relationalJoin(left, right, leftKey, rightKey, nil, false, true)
+}
+
+// FullOuterJoin keeps the elements of both sides without a match, see
+// LeftJoin and RightJoin.
+func FullOuterJoin(left, right, leftKey, rightKey interface{}) interface{} {
+	return must(FullOuterJoinE(left, right, leftKey, rightKey))
+}
+
+func FullOuterJoinE(left, right, leftKey, rightKey interface{}) (interface{}, error) {
+	return relationalJoin(left, right, leftKey, rightKey, nil, true, true)
+}
+
+// InnerJoinWith joins like InnerJoin and calls combine, a func(L, R) X, for
+// every pair, returning a []X.
+func InnerJoinWith(left, right, leftKey, rightKey, combine interface{}) interface{} {
+	return must(InnerJoinWithE(left, right, leftKey, rightKey, combine))
+}
+
+func InnerJoinWithE(left, right, leftKey, rightKey, combine interface{}) (interface{}, error) {
+	return relationalJoin(left, right, leftKey, rightKey, combine, false, false)
+}
+
+func LeftJoinWith(left, right, leftKey, rightKey, combine interface{}) interface{} {
+	return must(LeftJoinWithE(left, right, leftKey, rightKey, combine))
+}
+
+func LeftJoinWithE(left, right, leftKey, rightKey, combine interface{}) (interface{}, error) {
+	return relationalJoin(left, right, leftKey, rightKey, combine, true, false)
+}
+
+func RightJoinWith(left, right, leftKey, rightKey, combine interface{}) interface{} {
+	return must(RightJoinWithE(left, right, leftKey, rightKey, combine))
+}
+
+func RightJoinWithE(left, right, leftKey, rightKey, combine interface{}) (interface{}, error) {
+	return relationalJoin(left, right, leftKey, rightKey, combine, false, true)
+}
+
+func FullOuterJoinWith(left, right, leftKey, rightKey, combine interface{}) interface{} {
+	return must(FullOuterJoinWithE(left, right, leftKey, rightKey, combine))
+}
+
+func FullOuterJoinWithE(left, right, leftKey, rightKey, combine interface{}) (interface{}, error) {
+	return relationalJoin(left, right, leftKey, rightKey, combine, true, true)
+}
+
+// JoinPairType returns the element type of the slices returned by the joins
+// without a combiner.
+func JoinPairType(left, right reflect.Type) reflect.Type {
+	return reflect.StructOf([]reflect.StructField{
+		{Name: "Left", Type: left},
+		{Name: "Right", Type: right},
+	})
+}
+
+func relationalJoin(left, right, leftKey, rightKey, combine interface{}, keepLeft, keepRight bool) (res interface{}, err error) {
+	defer catch(&err)
+	lv, err := sliceValue(left)
+	if err != nil {
+		return nil, err
+	}
+	rv, err := sliceValue(right)
+	if err != nil {
+		return nil, err
+	}
+	lt, rt := lv.Type().Elem(), rv.Type().Elem()
+	lkt, lkey, err := compositeGetter(lt, []interface{}{leftKey})
+	if err != nil {
+		return nil, err
+	}
+	rkt, rkey, err := compositeGetter(rt, []interface{}{rightKey})
+	if err != nil {
+		return nil, err
+	}
+	// keys are matched without pointers and sql.Null* on both sides
+	base := keyBase(rkt)
+	rconv, _ := keyConverter(rkt, base)
+	lconv, ok := keyConverter(lkt, base)
+	if !ok {
+		return nil, &Error{Kind: ErrUnsupportedType, Type: rkt,
+			Msg: fmt.Sprintf("left key of type %v does not match right key of type %v", lkt, rkt)}
+	}
+
+	pair := pairFunc(lt, rt, combine)
+	out := reflect.MakeSlice(reflect.SliceOf(pair.out), 0, lv.Len())
+
+	matches := map[interface{}][]int{}
+	for i := 0; i < rv.Len(); i++ {
+		if k, _ := rconv(rkey(rv.Index(i))); k.IsValid() {
+			matches[k.Interface()] = append(matches[k.Interface()], i)
+		}
+	}
+	matched := make([]bool, rv.Len())
+	for i := 0; i < lv.Len(); i++ {
+		l := lv.Index(i)
+		var idx []int
+		if k, _ := lconv(lkey(l)); k.IsValid() {
+			idx = matches[k.Interface()]
+		}
+		for _, r := range idx {
+			matched[r] = true
+			out = reflect.Append(out, pair.fn(l, rv.Index(r)))
+		}
+		if len(idx) == 0 && keepLeft {
+			out = reflect.Append(out, pair.fn(l, reflect.Zero(rt)))
+		}
+	}
+	if keepRight {
+		for r, ok := range matched {
+			if !ok {
+				out = reflect.Append(out, pair.fn(reflect.Zero(lt), rv.Index(r)))
+			}
+		}
+	}
+	return out.Interface(), nil
+}
+
+type joinPair struct {
+	out reflect.Type
+	fn  func(l, r reflect.Value) reflect.Value
+}
+
+// pairFunc returns the result type and constructor of a joined pair, a
+// JoinPairType without combine.
+func pairFunc(lt, rt reflect.Type, combine interface{}) joinPair {
+	if combine == nil {
+		tp := JoinPairType(lt, rt)
+		return joinPair{tp, func(l, r reflect.Value) reflect.Value {
+			p := reflect.New(tp).Elem()
+			p.Field(0).Set(l)
+			p.Field(1).Set(r)
+			return p
+		}}
+	}
+	fv := reflect.ValueOf(combine)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func || ft.NumIn() != 2 || ft.NumOut() != 1 || !lt.AssignableTo(ft.In(0)) || !rt.AssignableTo(ft.In(1)) {
+		panic(badSignature(ft, fmt.Sprintf("expected func(%v, %v) with one return value", lt, rt)))
+	}
+	return joinPair{ft.Out(0), func(l, r reflect.Value) reflect.Value {
+		return fv.Call([]reflect.Value{l, r})[0]
+	}}
+}
@@ -0,0 +1,119 @@
+package generics
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type paymentAccount = struct {
+	Left  *Payment
+	Right *Account
+}
+
+func joinPayments() ([]*Payment, []*Account) {
+	return []*Payment{
+		{ID: 1, AccountID: 1},
+		{ID: 2, AccountID: 3},
+		{ID: 3, AccountID: 1},
+		{ID: 4, AccountID: 2},
+	}, []*Account{
+		{1, "one"},
+		{2, "two"},
+		{4, "four"},
+		{2, "two again"},
+	}
+}
+
+func pairsIDs(pairs []paymentAccount) string {
+	s := ""
+	for _, p := range pairs {
+		switch {
+		case p.Left == nil:
+			s += fmt.Sprintf("-/%s ", p.Right.Name)
+		case p.Right == nil:
+			s += fmt.Sprintf("%d/- ", p.Left.ID)
+		default:
+			s += fmt.Sprintf("%d/%s ", p.Left.ID, p.Right.Name)
+		}
+	}
+	return s
+}
+
+func TestRelationalJoins(t *testing.T) {
+	payments, accounts := joinPayments()
+	names := InnerJoinWith(payments, accounts, "AccountID", "ID", func(p *Payment, a *Account) string {
+		return fmt.Sprintf("%d:%s", p.ID, a.Name)
+	})
+	orphans := LeftJoinWith(payments, accounts, "AccountID", func(a *Account) int { return a.ID }, func(p *Payment, a *Account) bool {
+		return a == nil
+	})
+
+	tests := []struct{ Has, Want interface{} }{
+		{pairsIDs(InnerJoin(payments, accounts, "AccountID", "ID").([]paymentAccount)), "1/one 3/one 4/two 4/two again "},
+		{pairsIDs(LeftJoin(payments, accounts, "AccountID", "ID").([]paymentAccount)), "1/one 2/- 3/one 4/two 4/two again "},
+		{pairsIDs(RightJoin(payments, accounts, "AccountID", "ID").([]paymentAccount)), "1/one 3/one 4/two 4/two again -/four "},
+		{pairsIDs(FullOuterJoin(payments, accounts, "AccountID", "ID").([]paymentAccount)), "1/one 2/- 3/one 4/two 4/two again -/four "},
+		{pairsIDs(New(payments).LeftJoin(accounts, "AccountID", "ID").Cast().([]paymentAccount)), "1/one 2/- 3/one 4/two 4/two again "},
+		{pairsIDs(InnerJoin(payments, []*Account{}, "AccountID", "ID").([]paymentAccount)), ""},
+		{fmt.Sprint(names), "[1:one 3:one 4:two 4:two again]"},
+		{fmt.Sprint(orphans), "[false true false false false]"},
+		{JoinPairType(reflect.TypeOf(payments).Elem(), reflect.TypeOf(accounts).Elem()) == reflect.TypeOf(paymentAccount{}), true},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestRelationalJoinPointerKeys(t *testing.T) {
+	type node struct {
+		ID     int
+		Parent *int
+		Ref    sql.NullInt64
+	}
+	one, alsoOne := 1, 1
+	list := []*node{{1, nil, sql.NullInt64{}}, {2, &one, sql.NullInt64{Int64: 1, Valid: true}}, {3, &alsoOne, sql.NullInt64{}}}
+	ids := func(pairs interface{}) string {
+		s := ""
+		for _, p := range pairs.([]struct{ Left, Right *node }) {
+			s += fmt.Sprintf("%d-%d ", p.Left.ID, p.Right.ID)
+		}
+		return s
+	}
+
+	tests := []struct{ Has, Want interface{} }{
+		{ids(InnerJoin(list, list, "Parent", "Parent")), "2-2 2-3 3-2 3-3 "},
+		{ids(InnerJoin(list, list, "Ref", "Ref")), "2-2 "},
+		{ids(InnerJoin(list, list, "Parent", "Ref")), "2-2 3-2 "},
+		{ids(InnerJoin(list, list, "Parent", "ID")), "2-1 3-1 "},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestRelationalJoinErrors(t *testing.T) {
+	payments, accounts := joinPayments()
+	_, errList := InnerJoinE(payments, 1, "AccountID", "ID")
+	_, errKey := LeftJoinE(payments, accounts, "Missing", "ID")
+	_, errKeyType := RightJoinE(payments, accounts, "AccountID", "Name")
+	_, errCombine := FullOuterJoinWithE(payments, accounts, "AccountID", "ID", func(p *Payment) string { return "" })
+
+	tests := []struct{ Has, Want interface{} }{
+		{errors.Is(errList, ErrUnsupportedType), true},
+		{errors.Is(errKey, ErrFieldNotFound), true},
+		{errors.Is(errKeyType, ErrUnsupportedType), true},
+		{errors.Is(errCombine, ErrBadSignature), true},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
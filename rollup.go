@@ -0,0 +1,71 @@
+package generics
+
+import (
+	"reflect"
+)
+
+// RollupRow is one row of a Rollup. Keys holds a key for every level, nil
+// for the levels rolled up. Level is the number of keys the row is grouped
+// by: len(Keys) for detail rows, less for subtotals and 0 for the grand
+// total.
+type RollupRow struct {
+	Keys     []interface{}
+	RolledUp []bool
+	Level    int
+	Value    float64
+}
+
+// Subtotal reports whether the row is a subtotal or the grand total.
+func (r RollupRow) Subtotal() bool {
+	return r.Level < len(r.Keys)
+}
+
+// Rollup aggregates list with agg for every combination of keys and all of
+// their prefixes, like SQL's GROUP BY ROLLUP. Rows are ordered for
+// hierarchical reports: every group in key order as by GroupBy, followed by
+// its subtotal, and the grand total last.
+//
+//	for _, row := range Rollup(payments, []interface{}{"AccountID", "Month"}, AggregateOf(Sum, "Amount")) {
+//		if row.RolledUp[1] {
+//			// subtotal of an account or the grand total
+//		}
+//	}
+func Rollup(list interface{}, keys []interface{}, agg Aggregator) []RollupRow {
+	return must(RollupE(list, keys, agg)).([]RollupRow)
+}
+
+func RollupE(list interface{}, keys []interface{}, agg Aggregator) (res []RollupRow, err error) {
+	defer catch(&err)
+	v, err := sliceValue(list)
+	if err != nil {
+		return nil, err
+	}
+	if agg == nil {
+		return nil, &Error{Kind: ErrBadSignature, Msg: "expected an aggregator"}
+	}
+	if _, _, err := keyGetters(v.Type().Elem(), keys); err != nil {
+		return nil, err
+	}
+	return rollup(v, keys, agg, nil, nil), nil
+}
+
+func rollup(group reflect.Value, keys []interface{}, agg Aggregator, prefix []interface{}, res []RollupRow) []RollupRow {
+	level := len(prefix)
+	if level < len(keys) && group.Len() > 0 {
+		g := New(group.Interface()).GroupBy(keys[level])
+		for i, k := range g.keys {
+			res = rollup(g.groups[i], keys, agg, append(prefix[:level:level], k.Interface()), res)
+		}
+	}
+	row := RollupRow{
+		Keys:     make([]interface{}, len(keys)),
+		RolledUp: make([]bool, len(keys)),
+		Level:    level,
+		Value:    agg(group.Interface()),
+	}
+	copy(row.Keys, prefix)
+	for i := level; i < len(keys); i++ {
+		row.RolledUp[i] = true
+	}
+	return append(res, row)
+}
@@ -0,0 +1,74 @@
+package generics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func rollupString(rows []RollupRow) string {
+	s := ""
+	for _, r := range rows {
+		s += fmt.Sprintf("%v%v:%v ", r.Keys, r.Level, r.Value)
+	}
+	return s
+}
+
+func TestRollup(t *testing.T) {
+	rows := Rollup(groupedPayments(), []interface{}{"AccountID", "Currency"}, AggregateOf(Sum, "Amount"))
+	counts := New(groupedPayments()).Rollup([]interface{}{"Currency"}, CountOf)
+	empty := Rollup([]*groupedPayment{}, []interface{}{"AccountID", "Currency"}, CountOf)
+
+	tests := []struct{ Has, Want interface{} }{
+		{rollupString(rows), "[1 EUR]2:21 [1 USD]2:30 [1 <nil>]1:51 [2 USD]2:5 [2 <nil>]1:5 [3 EUR]2:50 [3 <nil>]1:50 [<nil> <nil>]0:106 "},
+		{rows[0].Subtotal(), false},
+		{rows[2].Subtotal(), true},
+		{fmt.Sprint(rows[2].RolledUp), "[false true]"},
+		{fmt.Sprint(rows[7].RolledUp), "[true true]"},
+		{rollupString(counts), "[EUR]1:4 [USD]1:2 [<nil>]0:6 "},
+		{rollupString(empty), "[<nil> <nil>]0:0 "},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestRollupErrors(t *testing.T) {
+	_, errList := RollupE(1, []interface{}{"AccountID"}, CountOf)
+	_, errKey := RollupE(groupedPayments(), []interface{}{"AccountID", "Missing"}, CountOf)
+	_, errKeys := RollupE(groupedPayments(), nil, CountOf)
+	_, errAgg := RollupE(groupedPayments(), []interface{}{"AccountID"}, nil)
+
+	tests := []struct{ Has, Want interface{} }{
+		{errors.Is(errList, ErrUnsupportedType), true},
+		{errors.Is(errKey, ErrFieldNotFound), true},
+		{errors.Is(errKeys, ErrBadSignature), true},
+		{errors.Is(errAgg, ErrBadSignature), true},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestRollupPointerKeys(t *testing.T) {
+	b, a := &Account{ID: 2, Name: "b"}, &Account{ID: 1, Name: "a"}
+	list := []*Payment{{ID: 1, Account: b}, {ID: 2, Account: a}, {ID: 3, Account: b}}
+	rows, err := RollupE(list, []interface{}{"Account", "ID"}, CountOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := ""
+	for _, r := range rows {
+		if acc, ok := r.Keys[0].(*Account); ok {
+			s += acc.Name
+		}
+		s += fmt.Sprintf("%v:%v ", r.Keys[1], r.Value)
+	}
+	if want := "b1:1 b3:1 b<nil>:2 a2:1 a<nil>:1 <nil>:3 "; s != want {
+		t.Errorf("want %q, was %q", want, s)
+	}
+}
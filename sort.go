@@ -0,0 +1,355 @@
+package generics
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortKey is one key of a sort. Key is an attribute name or a func as
+// accepted by Map, Desc reverses the order for this key only.
+//
+// Plain attribute names can be prefixed with "-" instead:
+//
+//	Sort(payments, "AccountID", "-Amount", Desc(func(p *Payment) string { return p.Name }))
+//
+// Nil pointers are sorted last, independent of the direction, unless
+// NilsFirst is set.
+type SortKey struct {
+	Key       interface{}
+	Desc      bool
+	NilsFirst bool
+}
+
+func Asc(key interface{}) SortKey {
+	return SortKey{Key: key}
+}
+
+func Desc(key interface{}) SortKey {
+	return SortKey{Key: key, Desc: true}
+}
+
+// NilsFirst sorts nil values of key before all others:
+//
+//	Sort(list, NilsFirst("-DeletedAt"))
+func NilsFirst(key interface{}) SortKey {
+	k := parseSortKey(key)
+	k.NilsFirst = true
+	return k
+}
+
+// Sort sorts list in place by the given keys, the first key taking
+// precedence. Ties are not guaranteed to keep their order, use SortStable for
+// that.
+func Sort(list interface{}, keys ...interface{}) {
+	must(nil, SortE(list, keys...))
+}
+
+func SortE(list interface{}, keys ...interface{}) error {
+	return sortList(false, list, keys, false)
+}
+
+// SortReverse sorts like Sort with the direction of every key reversed.
+func SortReverse(list interface{}, keys ...interface{}) {
+	must(nil, SortReverseE(list, keys...))
+}
+
+func SortReverseE(list interface{}, keys ...interface{}) error {
+	return sortList(false, list, keys, true)
+}
+
+// SortStable sorts like Sort but keeps the input order of equal elements.
+func SortStable(list interface{}, keys ...interface{}) {
+	must(nil, SortStableE(list, keys...))
+}
+
+func SortStableE(list interface{}, keys ...interface{}) error {
+	return sortList(true, list, keys, false)
+}
+
+// sortList extracts every key once per element, sorts a permutation of the
+// indexes on the extracted keys and then reorders list accordingly.
+func sortList(stable bool, list interface{}, keys []interface{}, reverse bool) (err error) {
+	defer catch(&err)
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice {
+		return unsupportedType(reflect.TypeOf(list), "expected slice")
+	}
+	compiled, err := compileSortKeys(v.Type().Elem(), keys, reverse)
+	if err != nil {
+		return err
+	}
+	cmps := make([]func(i, j int) int, len(compiled))
+	for k, key := range compiled {
+		cmps[k] = key.decorate(v)
+	}
+
+	perm := make([]int, v.Len())
+	for i := range perm {
+		perm[i] = i
+	}
+	less := func(a, b int) bool {
+		for _, cmp := range cmps {
+			if c := cmp(perm[a], perm[b]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	}
+	if stable {
+		sort.SliceStable(perm, less)
+	} else {
+		sort.Slice(perm, less)
+	}
+
+	sorted := reflect.MakeSlice(v.Type(), len(perm), len(perm))
+	for i, p := range perm {
+		sorted.Index(i).Set(v.Index(p))
+	}
+	reflect.Copy(v, sorted)
+	return nil
+}
+
+type compiledSortKey struct {
+	tp        reflect.Type
+	getter    func(v reflect.Value) reflect.Value
+	cmp       func(a, b reflect.Value) int
+	desc      bool
+	nilsFirst bool
+}
+
+// compare orders nil values according to nilsFirst regardless of the
+// direction of the key.
+func (k *compiledSortKey) compare(a, b reflect.Value) int {
+	an, bn := isNil(a), isNil(b)
+	switch {
+	case an && bn:
+		return 0
+	case an != bn:
+		if an == k.nilsFirst {
+			return -1
+		}
+		return 1
+	}
+	c := k.cmp(a, b)
+	if k.desc {
+		return -c
+	}
+	return c
+}
+
+// decorate extracts the key of every element of v and returns a comparison
+// of the elements by their index. Keys of plain ordered kinds are stored
+// unboxed.
+func (k *compiledSortKey) decorate(v reflect.Value) func(i, j int) int {
+	values := make([]reflect.Value, v.Len())
+	for i := range values {
+		values[i] = k.getter(v.Index(i))
+	}
+	if plainKind(k.tp) {
+		switch k.tp.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return unboxed(values, reflect.Value.Int, k.desc)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return unboxed(values, reflect.Value.Uint, k.desc)
+		case reflect.Float32, reflect.Float64:
+			return unboxed(values, reflect.Value.Float, k.desc)
+		case reflect.String:
+			return unboxed(values, reflect.Value.String, k.desc)
+		}
+	}
+	return func(i, j int) int {
+		return k.compare(values[i], values[j])
+	}
+}
+
+func unboxed[T int64 | uint64 | float64 | string](values []reflect.Value, get func(reflect.Value) T, desc bool) func(i, j int) int {
+	keys := make([]T, len(values))
+	for i, v := range values {
+		keys[i] = get(v)
+	}
+	if desc {
+		return func(i, j int) int { return compareOrdered(keys[j], keys[i]) }
+	}
+	return func(i, j int) int { return compareOrdered(keys[i], keys[j]) }
+}
+
+func compileSortKeys(el reflect.Type, keys []interface{}, reverse bool) ([]*compiledSortKey, error) {
+	if len(keys) == 0 {
+		return nil, &Error{Kind: ErrBadSignature, Type: el, Msg: "expected at least one sort key"}
+	}
+	compiled := make([]*compiledSortKey, 0, len(keys))
+	for _, k := range keys {
+		key := parseSortKey(k)
+		tp, getter, err := newGetter(el, key.Key)
+		if err != nil {
+			return nil, err
+		}
+		cmp, err := compareFunc(tp)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, &compiledSortKey{tp: tp, getter: getter, cmp: cmp, desc: key.Desc != reverse, nilsFirst: key.NilsFirst})
+	}
+	return compiled, nil
+}
+
+func parseSortKey(key interface{}) SortKey {
+	switch k := key.(type) {
+	case SortKey:
+		return k
+	case string:
+		if strings.HasPrefix(k, "-") {
+			return SortKey{Key: k[1:], Desc: true}
+		}
+		return SortKey{Key: strings.TrimPrefix(k, "+")}
+	}
+	return SortKey{Key: key}
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	intType  = reflect.TypeOf(0)
+	boolType = reflect.TypeOf(true)
+)
+
+// compareFunc returns a three-way comparison for values of type t. In order
+// of precedence it uses a Compare(T) int method, a Less(T) bool method, an
+// underlying or embedded time.Time and finally the kind of t.
+func compareFunc(t reflect.Type) (func(a, b reflect.Value) int, error) {
+	if m, ok := comparisonMethod(t, "Compare", intType); ok {
+		return func(a, b reflect.Value) int {
+			return int(callMethod(a, m, b).Int())
+		}, nil
+	}
+	if m, ok := comparisonMethod(t, "Less", boolType); ok {
+		return func(a, b reflect.Value) int {
+			switch {
+			case callMethod(a, m, b).Bool():
+				return -1
+			case callMethod(b, m, a).Bool():
+				return 1
+			}
+			return 0
+		}, nil
+	}
+	if t != timeType && t.ConvertibleTo(timeType) {
+		return func(a, b reflect.Value) int {
+			return compareTimes(a.Convert(timeType), b.Convert(timeType))
+		}, nil
+	}
+	if f, ok := embeddedTime(t); ok {
+		return func(a, b reflect.Value) int {
+			return compareTimes(a.FieldByIndex(f.Index), b.FieldByIndex(f.Index))
+		}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b reflect.Value) int { return compareOrdered(a.Int(), b.Int()) }, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b reflect.Value) int { return compareOrdered(a.Uint(), b.Uint()) }, nil
+	case reflect.Float32, reflect.Float64:
+		return func(a, b reflect.Value) int { return compareOrdered(a.Float(), b.Float()) }, nil
+	case reflect.String:
+		return func(a, b reflect.Value) int { return compareOrdered(a.String(), b.String()) }, nil
+	case reflect.Bool:
+		return func(a, b reflect.Value) int { return compareBools(a.Bool(), b.Bool()) }, nil
+	case reflect.Ptr:
+		cmp, err := compareFunc(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		// nested pointers sort nil values last
+		inner := &compiledSortKey{cmp: cmp}
+		return func(a, b reflect.Value) int {
+			return inner.compare(a.Elem(), b.Elem())
+		}, nil
+	case reflect.Interface:
+		// the concrete type is only known per element
+		return func(a, b reflect.Value) int {
+			a, b = a.Elem(), b.Elem()
+			if a.Type() != b.Type() {
+				panic(unsupportedType(b.Type(), fmt.Sprintf("cannot compare %v with different type", a.Type())))
+			}
+			cmp, err := compareFunc(a.Type())
+			if err != nil {
+				panic(err)
+			}
+			inner := &compiledSortKey{cmp: cmp}
+			return inner.compare(a, b)
+		}, nil
+	}
+	return nil, unsupportedType(t, "expected sort key of an ordered kind, time.Time or a type with a Compare or Less method")
+}
+
+// plainKind reports whether compareFunc compares t by its kind alone.
+func plainKind(t reflect.Type) bool {
+	if _, ok := comparisonMethod(t, "Compare", intType); ok {
+		return false
+	}
+	if _, ok := comparisonMethod(t, "Less", boolType); ok {
+		return false
+	}
+	_, embedded := embeddedTime(t)
+	return !embedded && !t.ConvertibleTo(timeType)
+}
+
+// comparisonMethod looks for a method name(T) out on t or *t.
+func comparisonMethod(t reflect.Type, name string, out reflect.Type) (reflect.Method, bool) {
+	for _, mt := range []reflect.Type{t, reflect.PtrTo(t)} {
+		m, ok := mt.MethodByName(name)
+		if !ok {
+			continue
+		}
+		ft := m.Type
+		if ft.NumIn() == 2 && ft.In(1) == t && ft.NumOut() == 1 && ft.Out(0) == out {
+			return m, true
+		}
+	}
+	return reflect.Method{}, false
+}
+
+func embeddedTime(t reflect.Type) (reflect.StructField, bool) {
+	if t.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+	f, ok := t.FieldByName("Time")
+	return f, ok && f.Anonymous && f.Type == timeType
+}
+
+func compareTimes(a, b reflect.Value) int {
+	return a.Interface().(time.Time).Compare(b.Interface().(time.Time))
+}
+
+func compareOrdered[T int64 | uint64 | float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case b:
+		return -1
+	}
+	return 1
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package generics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type sortRecord struct {
+	AccountID int
+	Amount    float64
+	Name      string
+}
+
+func sortRecords() []*sortRecord {
+	return []*sortRecord{
+		{2, 10, "b"},
+		{1, 5, "c"},
+		{1, 20, "a"},
+		{2, 10, "a"},
+		{1, 5, "a"},
+	}
+}
+
+func sortedNames(list []*sortRecord) string {
+	return fmt.Sprint(Map(list, func(r *sortRecord) string { return fmt.Sprintf("%d-%v-%s", r.AccountID, r.Amount, r.Name) }))
+}
+
+func TestSortMultipleKeys(t *testing.T) {
+	name := func(r *sortRecord) string { return r.Name }
+
+	tests := []struct {
+		Keys []interface{}
+		Want string
+	}{
+		{[]interface{}{"AccountID", "-Amount", "Name"}, "[1-20-a 1-5-a 1-5-c 2-10-a 2-10-b]"},
+		{[]interface{}{"-AccountID", "+Amount", Desc(name)}, "[2-10-b 2-10-a 1-5-c 1-5-a 1-20-a]"},
+		{[]interface{}{Asc(name), Desc("AccountID"), "Amount"}, "[2-10-a 1-5-a 1-20-a 2-10-b 1-5-c]"},
+	}
+	for i, tc := range tests {
+		list := sortRecords()
+		Sort(list, tc.Keys...)
+		if has := sortedNames(list); has != tc.Want {
+			t.Errorf("%d: want %s, was %s", i+1, tc.Want, has)
+		}
+	}
+}
+
+func TestSortReverseMultipleKeys(t *testing.T) {
+	list := sortRecords()
+	SortReverse(list, "AccountID", "-Amount", "Name")
+	if has, want := sortedNames(list), "[2-10-b 2-10-a 1-5-c 1-5-a 1-20-a]"; has != want {
+		t.Errorf("want %s, was %s", want, has)
+	}
+}
+
+func TestSortStable(t *testing.T) {
+	list := sortRecords()
+	SortStable(list, "AccountID")
+	if has, want := sortedNames(list), "[1-5-c 1-20-a 1-5-a 2-10-b 2-10-a]"; has != want {
+		t.Errorf("want %s, was %s", want, has)
+	}
+
+	list = sortRecords()
+	New(list).SortStable("-Amount")
+	if has, want := sortedNames(list), "[1-20-a 2-10-b 2-10-a 1-5-c 1-5-a]"; has != want {
+		t.Errorf("want %s, was %s", want, has)
+	}
+}
+
+type status string
+
+type version struct{ Major, Minor int }
+
+func (v version) Compare(o version) int {
+	if v.Major != o.Major {
+		return v.Major - o.Major
+	}
+	return v.Minor - o.Minor
+}
+
+type priority int
+
+func (p *priority) Less(o priority) bool { return *p > o }
+
+type created struct {
+	time.Time
+}
+
+type stamp time.Time
+
+func TestSortKinds(t *testing.T) {
+	type kinds struct {
+		ID       int64
+		Small    uint32
+		Ratio    float32
+		Active   bool
+		Timeout  time.Duration
+		Status   status
+		Version  version
+		Priority priority
+		Created  created
+		Stamp    stamp
+	}
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	list := []kinds{
+		{2, 20, 0.2, true, time.Second, "b", version{1, 10}, 2, created{t0.Add(time.Hour)}, stamp(t0)},
+		{1, 30, 0.3, false, time.Minute, "c", version{1, 2}, 3, created{t0}, stamp(t0.Add(time.Hour))},
+		{3, 10, 0.1, false, time.Millisecond, "a", version{2, 0}, 1, created{t0.Add(time.Minute)}, stamp(t0.Add(time.Minute))},
+	}
+	ids := func() string { return fmt.Sprint(Map(list, "ID")) }
+
+	tests := []struct {
+		Key  string
+		Want string
+	}{
+		{"ID", "[1 2 3]"},
+		{"Small", "[3 2 1]"},
+		{"Ratio", "[3 2 1]"},
+		{"Active", "[3 1 2]"},
+		{"Timeout", "[3 2 1]"},
+		{"Status", "[3 2 1]"},
+		{"Version", "[1 2 3]"},
+		{"Priority", "[1 2 3]"},
+		{"Created", "[1 3 2]"},
+		{"Stamp", "[2 3 1]"},
+	}
+	for _, tc := range tests {
+		SortStable(list, tc.Key)
+		if has := ids(); has != tc.Want {
+			t.Errorf("%s: want %s, was %s", tc.Key, tc.Want, has)
+		}
+	}
+}
+
+func TestSortNils(t *testing.T) {
+	one, two := 1, 2
+	type nullable struct {
+		Name  string
+		Value *int
+	}
+	list := []*nullable{{"a", &two}, {"b", nil}, {"c", &one}}
+	names := func() string { return fmt.Sprint(Map(list, "Name")) }
+
+	tests := []struct {
+		Key  interface{}
+		Want string
+	}{
+		{"Value", "[c a b]"},
+		{"-Value", "[a c b]"},
+		{NilsFirst("Value"), "[b c a]"},
+		{NilsFirst("-Value"), "[b a c]"},
+		{SortKey{Key: "Value", Desc: true, NilsFirst: true}, "[b a c]"},
+	}
+	for i, tc := range tests {
+		Sort(list, tc.Key)
+		if has := names(); has != tc.Want {
+			t.Errorf("%d: want %s, was %s", i+1, tc.Want, has)
+		}
+	}
+}
+
+func TestSortUnsupported(t *testing.T) {
+	type unsupported struct {
+		Tags []string
+	}
+	if err := SortE([]unsupported{}, "Tags"); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, was %v", err)
+	}
+}
@@ -0,0 +1,366 @@
+package generics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// Max returns the largest number in in, or NaN if in is empty. Like all
+// statistics functions it panics on non-numeric elements, see MaxE.
+func Max(in interface{}) float64 {
+	return stat(MaxE(in))
+}
+
+// MaxE returns the largest number in in, converted with opts as by Numbers.
+// Like all E statistics functions it returns ErrEmpty for empty input and
+// ErrUnsupportedType for non-numeric elements, both with a NaN result.
+func MaxE(in interface{}, opts ...NumberOption) (float64, error) {
+	return extreme(in, opts, func(a, b float64) bool { return a > b })
+}
+
+// Min returns the smallest number in in, or NaN if in is empty.
+func Min(in interface{}) float64 {
+	return stat(MinE(in))
+}
+
+func MinE(in interface{}, opts ...NumberOption) (float64, error) {
+	return extreme(in, opts, func(a, b float64) bool { return a < b })
+}
+
+func extreme(in interface{}, opts []NumberOption, better func(a, b float64) bool) (float64, error) {
+	values, err := nonEmpty(in, opts, "extreme")
+	if err != nil {
+		return math.NaN(), err
+	}
+	res := values[0]
+	for _, v := range values[1:] {
+		if better(v, res) {
+			res = v
+		}
+	}
+	return res, nil
+}
+
+func Sum(in interface{}) float64 {
+	return stat(SumE(in))
+}
+
+// SumE returns the sum of in, 0 if in is empty.
+func SumE(in interface{}, opts ...NumberOption) (float64, error) {
+	values, err := Numbers(in, opts...)
+	if err != nil {
+		return math.NaN(), err
+	}
+	return sum(values), nil
+}
+
+func sum(values []float64) (sum float64) {
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+// Average returns the arithmetic mean of in, or NaN if in is empty.
+func Average(in interface{}) float64 {
+	return stat(AverageE(in))
+}
+
+func AverageE(in interface{}, opts ...NumberOption) (float64, error) {
+	values, err := nonEmpty(in, opts, "average")
+	if err != nil {
+		return math.NaN(), err
+	}
+	return sum(values) / float64(len(values)), nil
+}
+
+// Median returns the middle number of in, or the mean of the two middle
+// numbers for an even count. It is NaN if in is empty.
+func Median(in interface{}) float64 {
+	return stat(MedianE(in))
+}
+
+func MedianE(in interface{}, opts ...NumberOption) (float64, error) {
+	return Linear.Percentile(in, 50, opts...)
+}
+
+// Mode returns the most frequent number in in, the smallest one if several
+// are equally frequent. It is NaN if in is empty.
+func Mode(in interface{}) float64 {
+	return stat(ModeE(in))
+}
+
+func ModeE(in interface{}, opts ...NumberOption) (float64, error) {
+	values, err := nonEmpty(in, opts, "mode")
+	if err != nil {
+		return math.NaN(), err
+	}
+	values = sorted(values)
+	mode, best := math.NaN(), 0
+	for i := 0; i < len(values); {
+		j := i
+		for j < len(values) && values[j] == values[i] {
+			j++
+		}
+		if j-i > best {
+			mode, best = values[i], j-i
+		}
+		i = j
+	}
+	return mode, nil
+}
+
+// Variance returns the population variance of in, or NaN if in is empty.
+func Variance(in interface{}) float64 {
+	return stat(VarianceE(in))
+}
+
+func VarianceE(in interface{}, opts ...NumberOption) (float64, error) {
+	values, err := nonEmpty(in, opts, "variance")
+	if err != nil {
+		return math.NaN(), err
+	}
+	mean := sum(values) / float64(len(values))
+	var res float64
+	for _, v := range values {
+		res += (v - mean) * (v - mean)
+	}
+	return res / float64(len(values)), nil
+}
+
+// StdDev returns the population standard deviation of in, or NaN if in is
+// empty.
+func StdDev(in interface{}) float64 {
+	return stat(StdDevE(in))
+}
+
+func StdDevE(in interface{}, opts ...NumberOption) (float64, error) {
+	res, err := VarianceE(in, opts...)
+	return math.Sqrt(res), err
+}
+
+// nonEmpty converts in with opts and returns ErrEmpty if there are no values.
+func nonEmpty(in interface{}, opts []NumberOption, what string) ([]float64, error) {
+	values, err := Numbers(in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, &Error{Kind: ErrEmpty, Type: reflect.TypeOf(in), Msg: fmt.Sprintf("no values to compute %s of", what)}
+	}
+	return values, nil
+}
+
+// stat returns the result of an E statistics function for its panicking
+// variant: NaN for empty input or an out of range percentile, a panic for any
+// other error.
+func stat(res float64, err error) float64 {
+	if err != nil && !errors.Is(err, ErrEmpty) && !errors.Is(err, ErrIndexOutOfRange) {
+		panic(err)
+	}
+	return res
+}
+
+// PercentileMethod selects how a percentile is computed from the sorted
+// values.
+type PercentileMethod int
+
+const (
+	// NearestRank returns the smallest value with at least perc percent of
+	// all values less or equal to it.
+	NearestRank PercentileMethod = iota
+	// Linear interpolates between the closest ranks, counting the first value
+	// as the 0th and the last as the 100th percentile (PERCENTILE.INC).
+	Linear
+	// Exclusive interpolates between the closest ranks, counting values as
+	// 1/(n+1) steps (PERCENTILE.EXC). Percentiles outside of
+	// [100/(n+1), 100*n/(n+1)] are out of range.
+	Exclusive
+)
+
+// Percentile returns the perc percentile of in by NearestRank, or NaN if in
+// is empty or perc is not within [0, 100].
+func Percentile(i interface{}, perc int) float64 {
+	return stat(PercentileE(i, perc))
+}
+
+func PercentileE(i interface{}, perc int, opts ...NumberOption) (float64, error) {
+	return NearestRank.Percentile(i, float64(perc), opts...)
+}
+
+// Percentile returns the perc percentile of in computed with method m, in
+// converted with opts as by Numbers. It returns ErrEmpty for empty input,
+// ErrIndexOutOfRange if perc is out of range and ErrUnsupportedType for
+// non-numeric input, all with a NaN result.
+func (m PercentileMethod) Percentile(in interface{}, perc float64, opts ...NumberOption) (float64, error) {
+	values, err := nonEmpty(in, opts, "percentile")
+	if err != nil {
+		return math.NaN(), err
+	}
+	values = sorted(values)
+	n := len(values)
+	outOfRange := func() (float64, error) {
+		return math.NaN(), &Error{Kind: ErrIndexOutOfRange, Type: reflect.TypeOf(in), Msg: fmt.Sprintf("unable to get percentile %v of %d values", perc, n)}
+	}
+	if perc < 0 || perc > 100 || math.IsNaN(perc) {
+		return outOfRange()
+	}
+	switch m {
+	case NearestRank:
+		rank := int(math.Ceil(perc / 100 * float64(n)))
+		if rank < 1 {
+			rank = 1
+		}
+		return values[rank-1], nil
+	case Linear:
+		return interpolate(values, perc/100*float64(n-1)), nil
+	case Exclusive:
+		pos := perc/100*float64(n+1) - 1
+		if pos < 0 || pos > float64(n-1) {
+			return outOfRange()
+		}
+		return interpolate(values, pos), nil
+	}
+	return math.NaN(), &Error{Kind: ErrUnsupportedType, Msg: fmt.Sprintf("unknown percentile method %d", m)}
+}
+
+// interpolate returns the value at the fractional index pos of values.
+func interpolate(values []float64, pos float64) float64 {
+	lower := math.Floor(pos)
+	i := int(lower)
+	if i+1 >= len(values) {
+		return values[i]
+	}
+	return values[i] + (pos-lower)*(values[i+1]-values[i])
+}
+
+func sorted(values []float64) []float64 {
+	out := make([]float64, len(values))
+	copy(out, values)
+	sort.Float64s(out)
+	return out
+}
+
+// SumBy returns the sum of key, an attribute or func as accepted by Map, over
+// all elements of list.
+func SumBy(list interface{}, key interface{}) float64 {
+	res, err := SumByE(list, key)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func SumByE(list interface{}, key interface{}, opts ...NumberOption) (float64, error) {
+	values, _, err := keyFloats(list, key, opts)
+	if err != nil {
+		return math.NaN(), err
+	}
+	return sum(values), nil
+}
+
+// AverageBy returns the mean of key over all elements of list, NaN if list is
+// empty.
+func AverageBy(list interface{}, key interface{}) float64 {
+	return stat(AverageByE(list, key))
+}
+
+func AverageByE(list interface{}, key interface{}, opts ...NumberOption) (float64, error) {
+	values, _, err := keyFloats(list, key, opts)
+	if err != nil {
+		return math.NaN(), err
+	}
+	return AverageE(values)
+}
+
+// PercentileBy returns the perc percentile of key by NearestRank, NaN if list
+// is empty.
+func PercentileBy(list interface{}, key interface{}, perc int) float64 {
+	return stat(PercentileByE(list, key, perc))
+}
+
+func PercentileByE(list interface{}, key interface{}, perc int, opts ...NumberOption) (float64, error) {
+	values, _, err := keyFloats(list, key, opts)
+	if err != nil {
+		return math.NaN(), err
+	}
+	return PercentileE(values, perc)
+}
+
+// MaxBy returns the element of list with the largest key and that key. The
+// first one wins ties. For an empty list it returns the zero element and NaN.
+func MaxBy(list interface{}, key interface{}) (interface{}, float64) {
+	el, res, err := MaxByE(list, key)
+	if err != nil {
+		panic(err)
+	}
+	return el, res
+}
+
+func MaxByE(list interface{}, key interface{}, opts ...NumberOption) (interface{}, float64, error) {
+	return extremeBy(list, key, opts, func(a, b float64) bool { return a > b })
+}
+
+// MinBy returns the element of list with the smallest key and that key, see
+// MaxBy.
+func MinBy(list interface{}, key interface{}) (interface{}, float64) {
+	el, res, err := MinByE(list, key)
+	if err != nil {
+		panic(err)
+	}
+	return el, res
+}
+
+func MinByE(list interface{}, key interface{}, opts ...NumberOption) (interface{}, float64, error) {
+	return extremeBy(list, key, opts, func(a, b float64) bool { return a < b })
+}
+
+func extremeBy(list interface{}, key interface{}, opts []NumberOption, better func(a, b float64) bool) (interface{}, float64, error) {
+	values, elements, err := keyFloats(list, key, opts)
+	if err != nil {
+		return nil, math.NaN(), err
+	}
+	if len(values) == 0 {
+		v, _ := sliceValue(list)
+		return nullType(v.Type().Elem()), math.NaN(), nil
+	}
+	best := 0
+	for i, v := range values[1:] {
+		if better(v, values[best]) {
+			best = i + 1
+		}
+	}
+	return elements[best].Interface(), values[best], nil
+}
+
+// keyFloats returns the numeric keys of all elements of list, converted with
+// opts as by Numbers, and the elements they belong to.
+func keyFloats(list interface{}, key interface{}, opts []NumberOption) (values []float64, elements []reflect.Value, err error) {
+	defer catch(&err)
+	v, err := sliceValue(list)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, getter, err := newGetter(v.Type().Elem(), key)
+	if err != nil {
+		return nil, nil, err
+	}
+	o := newNumberOptions(opts)
+	values = make([]float64, 0, v.Len())
+	elements = make([]reflect.Value, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		el := v.Index(i)
+		f, ok, err := toFloat(getter(el), o)
+		if err != nil {
+			return nil, nil, elementError(err, i)
+		}
+		if ok {
+			values = append(values, f)
+			elements = append(elements, el)
+		}
+	}
+	return values, elements, nil
+}
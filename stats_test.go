@@ -0,0 +1,195 @@
+package generics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	values := []int{4, 1, 3, 2, 4}
+
+	tests := []struct {
+		Name string
+		Has  float64
+		Want float64
+	}{
+		{"Max", Max(values), 4},
+		{"Max negative", Max([]int{-5, -3}), -3},
+		{"Min", Min([]int{4, 7}), 4},
+		{"Min negative", Min([]int{-5, -3}), -5},
+		{"Sum", Sum(values), 14},
+		{"Sum empty", Sum([]int{}), 0},
+		{"Average", Average(values), 2.8},
+		{"Median odd", Median(values), 3},
+		{"Median even", Median([]int{4, 1, 2, 3}), 2.5},
+		{"Mode", Mode(values), 4},
+		{"Mode tie", Mode([]int{3, 1, 3, 1}), 1},
+		{"Variance", Variance([]int{2, 4, 4, 4, 5, 5, 7, 9}), 4},
+		{"StdDev", StdDev([]int{2, 4, 4, 4, 5, 5, 7, 9}), 2},
+		{"Percentile 0", Percentile(values, 0), 1},
+		{"Percentile 50", Percentile(values, 50), 3},
+		{"Percentile 100", Percentile(values, 100), 4},
+	}
+	for _, tc := range tests {
+		if math.Abs(tc.Has-tc.Want) > 1e-9 {
+			t.Errorf("%s: want %v, was %v", tc.Name, tc.Want, tc.Has)
+		}
+	}
+}
+
+func TestStatsEmpty(t *testing.T) {
+	empty := []float64{}
+	for name, v := range map[string]float64{
+		"Max":        Max(empty),
+		"Min":        Min(empty),
+		"Average":    Average(empty),
+		"Median":     Median(empty),
+		"Mode":       Mode(empty),
+		"Variance":   Variance(empty),
+		"StdDev":     StdDev(empty),
+		"Percentile": Percentile(empty, 50),
+	} {
+		if !math.IsNaN(v) {
+			t.Errorf("%s: expected NaN, was %v", name, v)
+		}
+	}
+	if _, err := PercentileE(empty, 50); !errors.Is(err, ErrEmpty) {
+		t.Errorf("expected ErrEmpty, was %v", err)
+	}
+}
+
+func TestPercentileMethods(t *testing.T) {
+	values := []float64{15, 20, 35, 40, 50}
+
+	tests := []struct {
+		Method PercentileMethod
+		Perc   float64
+		Want   string
+	}{
+		{NearestRank, 5, "15"},
+		{NearestRank, 30, "20"},
+		{NearestRank, 40, "20"},
+		{NearestRank, 50, "35"},
+		{NearestRank, 100, "50"},
+		{Linear, 0, "15"},
+		{Linear, 40, "29"},
+		{Linear, 75, "40"},
+		{Linear, 100, "50"},
+		{Exclusive, 40, "26"},
+		{Exclusive, 75, "45"},
+		{Exclusive, 10, "ErrIndexOutOfRange"},
+		{Exclusive, 90, "ErrIndexOutOfRange"},
+		{Linear, 101, "ErrIndexOutOfRange"},
+		{NearestRank, -1, "ErrIndexOutOfRange"},
+	}
+	for i, tc := range tests {
+		res, err := tc.Method.Percentile(values, tc.Perc)
+		has := fmt.Sprintf("%.10g", res)
+		if errors.Is(err, ErrIndexOutOfRange) {
+			has = "ErrIndexOutOfRange"
+		}
+		if has != tc.Want {
+			t.Errorf("%d: want %s, was %s (%v)", i+1, tc.Want, has, err)
+		}
+	}
+}
+
+func TestAggregationsBy(t *testing.T) {
+	type payment struct {
+		ID     int
+		Amount float64
+	}
+	payments := []*payment{{1, 10}, {2, 30}, {3, 20}, {4, 30}, {5, 5}}
+	amount := func(p *payment) float64 { return p.Amount }
+
+	maxEl, maxValue := MaxBy(payments, "Amount")
+	minEl, minValue := New(payments).MinBy(amount)
+	emptyEl, emptyValue := MaxBy([]*payment{}, "Amount")
+
+	tests := []struct{ Has, Want interface{} }{
+		{SumBy(payments, "Amount"), 95.0},
+		{New(payments).SumBy(amount), 95.0},
+		{AverageBy(payments, "Amount"), 19.0},
+		{New(payments).AverageBy("Amount"), 19.0},
+		{PercentileBy(payments, "Amount", 50), 20.0},
+		{New(payments).PercentileBy("Amount", 100), 30.0},
+		{maxEl.(*payment).ID, 2},
+		{maxValue, 30.0},
+		{minEl.(*payment).ID, 5},
+		{minValue, 5.0},
+		{emptyEl == nil, true},
+		{math.IsNaN(emptyValue), true},
+		{math.IsNaN(AverageBy([]*payment{}, "Amount")), true},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+
+	if _, err := AverageByE([]*payment{}, "Amount"); !errors.Is(err, ErrEmpty) {
+		t.Errorf("expected ErrEmpty, was %v", err)
+	}
+	if el, _ := MaxBy(&[]*payment{}, "Amount"); el != nil {
+		t.Errorf("expected nil element, was %#v", el)
+	}
+	if el, _ := MinBy(&[]payment{}, "Amount"); el != (payment{}) {
+		t.Errorf("expected zero element, was %#v", el)
+	}
+	if _, err := SumByE(payments, "Missing"); !errors.Is(err, ErrFieldNotFound) {
+		t.Errorf("expected ErrFieldNotFound, was %v", err)
+	}
+	if _, _, err := MaxByE(payments, func(p payment) float64 { return 0 }); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("expected ErrBadSignature, was %v", err)
+	}
+}
+
+func TestStatsE(t *testing.T) {
+	strs := []string{"4", "1", "3"}
+	one := 1
+	ptrs := []*int{&one, nil}
+	type stat func(in interface{}, opts ...NumberOption) (float64, error)
+	funcs := map[string]stat{
+		"Max": MaxE, "Min": MinE, "Sum": SumE, "Average": AverageE, "Median": MedianE,
+		"Mode": ModeE, "Variance": VarianceE, "StdDev": StdDevE,
+		"Percentile": func(in interface{}, opts ...NumberOption) (float64, error) { return PercentileE(in, 50, opts...) },
+	}
+	want := map[string]float64{
+		"Max": 4, "Min": 1, "Sum": 8, "Average": 8.0 / 3, "Median": 3,
+		"Mode": 1, "Variance": 14.0 / 9, "StdDev": math.Sqrt(14.0 / 9), "Percentile": 3,
+	}
+	for name, fn := range funcs {
+		if res, err := fn(strs, ParseStrings()); err != nil || math.Abs(res-want[name]) > 1e-9 {
+			t.Errorf("%s: want %v, was %v (%v)", name, want[name], res, err)
+		}
+		if _, err := fn(strs); !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("%s: want ErrUnsupportedType, was %v", name, err)
+		}
+		if _, err := fn(ptrs, NilAsError()); !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("%s: want ErrUnsupportedType for nil, was %v", name, err)
+		}
+		if _, err := fn([]int{}); name != "Sum" && !errors.Is(err, ErrEmpty) {
+			t.Errorf("%s: want ErrEmpty, was %v", name, err)
+		}
+	}
+
+	if res, err := SumByE([]struct{ A string }{{"1"}, {"2.5"}}, "A", ParseStrings()); err != nil || res != 3.5 {
+		t.Errorf("SumByE: want 3.5, was %v (%v)", res, err)
+	}
+	for name, fn := range map[string]func(){
+		"Percentile": func() { Percentile(strs, 50) },
+		"Median":     func() { Median(strs) },
+		"Max":        func() { Max(strs) },
+	} {
+		func() {
+			defer func() {
+				if err, ok := recover().(error); !ok || !errors.Is(err, ErrUnsupportedType) {
+					t.Errorf("%s: expected ErrUnsupportedType panic, was %v", name, err)
+				}
+			}()
+			fn()
+		}()
+	}
+}
@@ -0,0 +1,323 @@
+package generics
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Association kinds declared in generics struct tags.
+const (
+	BelongsTo  = "belongs_to"
+	HasOne     = "has_one"
+	HasMany    = "has_many"
+	ManyToMany = "many_to_many"
+)
+
+// Association is an association declared on a struct field with a generics
+// tag:
+//
+//	type Payment struct {
+//		OwnerID int
+//		Owner   *Account `generics:"belongs_to,fk=OwnerID,pk=ID"`
+//	}
+//
+//	type Account struct {
+//		ID       int
+//		Payments []*Payment `generics:"has_many,fk=AccountID"`
+//		Tags     []*Tag     `generics:"many_to_many,through=AccountTag,fk=AccountID,other=TagID"`
+//	}
+//
+// Omitted keys follow Join's naming convention: fk is the field name plus
+// "ID" for belongs_to and the struct name plus "ID" otherwise, pk is "ID",
+// and for many_to_many other is the related type name plus "ID" and other_pk
+// is "ID". Type is the element type of the related list.
+type Association struct {
+	Field           string
+	Kind            string
+	Type            reflect.Type
+	ForeignKey      string
+	PrimaryKey      string
+	Through         string
+	OtherKey        string
+	OtherPrimaryKey string
+}
+
+var associationCache sync.Map
+
+type cachedAssociations struct {
+	list []Association
+	err  error
+}
+
+// Associations returns the associations declared on the struct type t, or a
+// pointer to it, in field order. Misconfigured tags return ErrBadTag.
+func Associations(t reflect.Type) ([]Association, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if c, ok := associationCache.Load(t); ok {
+		return c.(cachedAssociations).list, c.(cachedAssociations).err
+	}
+	list, err := parseAssociations(t)
+	associationCache.Store(t, cachedAssociations{list, err})
+	return list, err
+}
+
+func parseAssociations(t reflect.Type) ([]Association, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, unsupportedType(t, "expected struct")
+	}
+	var list []Association
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("generics")
+		if !ok {
+			continue
+		}
+		a, err := parseAssociation(t, f, tag)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, a)
+	}
+	return list, nil
+}
+
+func parseAssociation(t reflect.Type, f reflect.StructField, tag string) (Association, error) {
+	bad := func(format string, args ...interface{}) error {
+		return &Error{Kind: ErrBadTag, Type: t, Field: f.Name,
+			Msg: fmt.Sprintf("%v.%s: ", t, f.Name) + fmt.Sprintf(format, args...)}
+	}
+	parts := strings.Split(tag, ",")
+	a := Association{Field: f.Name, Kind: strings.TrimSpace(parts[0]), PrimaryKey: "ID"}
+	switch a.Kind {
+	case BelongsTo, HasOne:
+		a.Type = f.Type
+	case HasMany, ManyToMany:
+		if f.Type.Kind() != reflect.Slice {
+			return a, bad("%s needs a slice field, was %v", a.Kind, f.Type)
+		}
+		a.Type = f.Type.Elem()
+	default:
+		return a, bad("unknown association %q", a.Kind)
+	}
+	if st := a.Type; st.Kind() != reflect.Struct && (st.Kind() != reflect.Ptr || st.Elem().Kind() != reflect.Struct) {
+		return a, bad("%s needs a struct or pointer to struct, was %v", a.Kind, a.Type)
+	}
+
+	for _, opt := range parts[1:] {
+		kv := strings.SplitN(strings.TrimSpace(opt), "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return a, bad("expected key=value, was %q", opt)
+		}
+		switch kv[0] {
+		case "fk":
+			a.ForeignKey = kv[1]
+		case "pk":
+			a.PrimaryKey = kv[1]
+		case "through":
+			a.Through = kv[1]
+		case "other":
+			a.OtherKey = kv[1]
+		case "other_pk":
+			a.OtherPrimaryKey = kv[1]
+		default:
+			return a, bad("unknown option %q", kv[0])
+		}
+	}
+
+	related := structName(a.Type)
+	switch a.Kind {
+	case BelongsTo:
+		if a.ForeignKey == "" {
+			a.ForeignKey = f.Name + "ID"
+		}
+	default:
+		if a.ForeignKey == "" {
+			a.ForeignKey = t.Name() + "ID"
+		}
+	}
+	if a.Kind == ManyToMany {
+		if a.Through == "" {
+			return a, bad("many_to_many needs through")
+		}
+		if a.OtherKey == "" {
+			a.OtherKey = related + "ID"
+		}
+		if a.OtherPrimaryKey == "" {
+			a.OtherPrimaryKey = "ID"
+		}
+		// the keys on the through type are checked by JoinAll
+		if _, _, err := keyGetters(t, splitKeys(a.PrimaryKey)); err != nil {
+			return a, bad("%v", err)
+		}
+		if _, _, err := keyGetters(a.Type, splitKeys(a.OtherPrimaryKey)); err != nil {
+			return a, bad("%v", err)
+		}
+		return a, nil
+	}
+	if a.Through != "" || a.OtherKey != "" || a.OtherPrimaryKey != "" {
+		return a, bad("through, other and other_pk only apply to many_to_many")
+	}
+	if a.Kind == BelongsTo {
+		return a, checkKeys(bad, t, a.ForeignKey, a.Type, a.PrimaryKey)
+	}
+	return a, checkKeys(bad, a.Type, a.ForeignKey, t, a.PrimaryKey)
+}
+
+// checkKeys checks that the foreign keys on t1 and the primary keys on t2
+// exist and that the foreign keys can be converted to the primary keys.
+func checkKeys(bad func(string, ...interface{}) error, t1 reflect.Type, keys1 string, t2 reflect.Type, keys2 string) error {
+	types1, _, err := keyGetters(t1, splitKeys(keys1))
+	if err != nil {
+		return bad("%v", err)
+	}
+	types2, _, err := keyGetters(t2, splitKeys(keys2))
+	if err != nil {
+		return bad("%v", err)
+	}
+	if len(types1) != len(types2) {
+		return bad("keys %s and %s differ in length", keys1, keys2)
+	}
+	for i := range types1 {
+		if _, ok := keyConverter(types1[i], types2[i]); !ok {
+			return bad("key %s of type %v does not match %s of type %v", keys1, types1[i], keys2, types2[i])
+		}
+	}
+	return nil
+}
+
+func structName(t reflect.Type) string {
+	return derefType(t).Name()
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// JoinAll loads every association declared on the element type of
+// collection, see Association, from the related lists. Every related list is
+// matched to the associations by its element type, so no two lists may share
+// one. A many_to_many also needs its through list, whose element type is
+// named by through, qualified with its package as in "models.AccountTag" if
+// the name alone is ambiguous. Associations without a related list are
+// skipped.
+//
+//	JoinAll(accounts, payments, tags, accountTags)
+func JoinAll(collection interface{}, related ...interface{}) {
+	must(nil, JoinAllE(collection, related...))
+}
+
+// JoinAll loads every association declared on the element type of the
+// collection, see JoinAll.
+func (c *Collection) JoinAll(related ...interface{}) *Collection {
+	return mustCollection(c.JoinAllE(related...))
+}
+
+func (c *Collection) JoinAllE(related ...interface{}) (*Collection, error) {
+	return c, JoinAllE(c.collection, related...)
+}
+
+func JoinAllE(collection interface{}, related ...interface{}) error {
+	sv, err := sliceValue(collection)
+	if err != nil {
+		return err
+	}
+	assocs, err := Associations(sv.Type().Elem())
+	if err != nil {
+		return err
+	}
+	byType := map[reflect.Type]interface{}{}
+	for _, list := range related {
+		t := reflect.TypeOf(list)
+		if t == nil || t.Kind() != reflect.Slice {
+			return unsupportedType(t, "expected related slice")
+		}
+		if _, ok := byType[t.Elem()]; ok {
+			return &Error{Kind: ErrAmbiguous, Type: t,
+				Msg: fmt.Sprintf("more than one related list of %v", t.Elem())}
+		}
+		byType[t.Elem()] = list
+	}
+
+	used := map[reflect.Type]bool{}
+	c := New(collection)
+	for _, a := range assocs {
+		list, ok := byType[a.Type]
+		if !ok {
+			continue
+		}
+		used[a.Type] = true
+		switch a.Kind {
+		case BelongsTo:
+			_, err = c.JoinGenericE(list, a.Field, a.ForeignKey, a.PrimaryKey)
+		case HasOne:
+			_, err = c.JoinOneGenericE(list, a.Field, a.ForeignKey, a.PrimaryKey)
+		case HasMany:
+			_, err = c.JoinManyGenericE(list, a.Field, a.ForeignKey, a.PrimaryKey)
+		case ManyToMany:
+			var through reflect.Type
+			if through, err = throughType(byType, sv.Type(), a); err != nil {
+				return err
+			}
+			used[through] = true
+			_, err = c.JoinThroughGenericE(byType[through], list, a.Field, a.ForeignKey, a.OtherKey, a.PrimaryKey, a.OtherPrimaryKey)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for t := range byType {
+		if !used[t] {
+			return &Error{Kind: ErrBadSignature, Type: t,
+				Msg: fmt.Sprintf("no association on %v for list of %v", sv.Type().Elem(), t)}
+		}
+	}
+	return nil
+}
+
+// throughType returns the element type of the through list of a, named by
+// its struct name or its package qualified name.
+func throughType(byType map[reflect.Type]interface{}, t reflect.Type, a Association) (reflect.Type, error) {
+	var found reflect.Type
+	for el := range byType {
+		if structName(el) != a.Through && derefType(el).String() != a.Through {
+			continue
+		}
+		if found != nil {
+			return nil, &Error{Kind: ErrAmbiguous, Type: t, Field: a.Field,
+				Msg: fmt.Sprintf("through lists of %v and %v for %s, qualify %s with its package", found, el, a.Field, a.Through)}
+		}
+		found = el
+	}
+	if found == nil {
+		return nil, &Error{Kind: ErrBadSignature, Type: t, Field: a.Field,
+			Msg: fmt.Sprintf("no through list of %s for %s", a.Through, a.Field)}
+	}
+	return found, nil
+}
+
+// belongsTo returns the belongs_to association of el to related, if declared.
+func belongsTo(el, related reflect.Type) (*Association, error) {
+	assocs, err := Associations(el)
+	if err != nil {
+		return nil, err
+	}
+	var found *Association
+	for i, a := range assocs {
+		if a.Kind != BelongsTo || a.Type != related {
+			continue
+		}
+		if found != nil {
+			return nil, &Error{Kind: ErrAmbiguous, Type: el, Field: a.Field,
+				Msg: fmt.Sprintf("%v belongs to %v as %s and %s, use JoinGeneric", el, related, found.Field, a.Field)}
+		}
+		found = &assocs[i]
+	}
+	return found, nil
+}
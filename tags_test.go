@@ -0,0 +1,175 @@
+package generics
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type taggedAccount struct {
+	ID       int
+	Payments []*taggedPayment `generics:"has_many,fk=OwnerID"`
+	Profile  *taggedProfile   `generics:"has_one,fk=AccountID"`
+	Tags     []*taggedTag     `generics:"many_to_many,through=taggedLink,fk=AccountID,other=TagID"`
+}
+
+type taggedPayment struct {
+	ID      int
+	OwnerID int
+	Owner   *taggedAccount `generics:"belongs_to"`
+}
+
+type taggedProfile struct {
+	AccountID int
+	Email     string
+}
+
+type taggedTag struct {
+	ID   int
+	Name string
+}
+
+type taggedLink struct {
+	AccountID int
+	TagID     int
+}
+
+func TestAssociations(t *testing.T) {
+	assocs, err := Associations(reflect.TypeOf(&taggedAccount{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payment, err := Associations(reflect.TypeOf(taggedPayment{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct{ Has, Want interface{} }{
+		{len(assocs), 3},
+		{fmt.Sprintf("%s %s %v %s %s", assocs[0].Field, assocs[0].Kind, assocs[0].Type, assocs[0].ForeignKey, assocs[0].PrimaryKey), "Payments has_many *generics.taggedPayment OwnerID ID"},
+		{assocs[1].Kind, HasOne},
+		{fmt.Sprintf("%s %s %s %s", assocs[2].Through, assocs[2].ForeignKey, assocs[2].OtherKey, assocs[2].OtherPrimaryKey), "taggedLink AccountID TagID ID"},
+		{fmt.Sprintf("%s %s %s", payment[0].Kind, payment[0].ForeignKey, payment[0].PrimaryKey), "belongs_to OwnerID ID"},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestJoinAll(t *testing.T) {
+	accounts := []*taggedAccount{{ID: 1}, {ID: 2}}
+	payments := []*taggedPayment{{ID: 1, OwnerID: 1}, {ID: 2, OwnerID: 2}, {ID: 3, OwnerID: 1}}
+	profiles := []*taggedProfile{{2, "two@example.com"}}
+	tags := []*taggedTag{{1, "new"}, {2, "vip"}}
+	links := []*taggedLink{{1, 2}, {2, 1}, {2, 2}}
+
+	JoinAll(accounts, payments, profiles, tags, links)
+	New(payments).Join(accounts)
+
+	tests := []struct{ Has, Want interface{} }{
+		{fmt.Sprint(Map(accounts[0].Payments, "ID")), "[1 3]"},
+		{fmt.Sprint(Map(accounts[1].Payments, "ID")), "[2]"},
+		{accounts[0].Profile == nil, true},
+		{accounts[1].Profile.Email, "two@example.com"},
+		{fmt.Sprint(Map(accounts[0].Tags, "Name")), "[vip]"},
+		{fmt.Sprint(Map(accounts[1].Tags, "Name")), "[new vip]"},
+		{payments[2].Owner.ID, 1},
+		{payments[1].Owner.ID, 2},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestJoinAllLists(t *testing.T) {
+	type qualified struct {
+		ID   int
+		Tags []*taggedTag `generics:"many_to_many,through=generics.taggedLink,fk=AccountID,other=TagID"`
+	}
+	type taggedLink struct {
+		AccountID int
+		TagID     int
+	}
+	tags := []*taggedTag{{1, "new"}, {2, "vip"}}
+	links := []*taggedLink{{1, 2}}
+	accounts := []*qualified{{ID: 1}}
+	_, err := New(accounts).JoinAllE(tags, links)
+	errDuplicate := JoinAllE([]*taggedAccount{}, tags, []*taggedTag{})
+	errThrough := JoinAllE([]*taggedAccount{}, tags, links, []*taggedLink{})
+	errMissing := JoinAllE([]*taggedAccount{}, tags)
+
+	tests := []struct{ Has, Want interface{} }{
+		{err, nil},
+		{fmt.Sprint(Map(accounts[0].Tags, "Name")), "[vip]"},
+		{errors.Is(errDuplicate, ErrAmbiguous), true},
+		{errors.Is(errThrough, ErrAmbiguous), true},
+		{errors.Is(errMissing, ErrBadSignature), true},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestAssociationTagErrors(t *testing.T) {
+	type unknown struct {
+		Account *Account `generics:"owns"`
+	}
+	type option struct {
+		Account *Account `generics:"belongs_to,key=ID"`
+	}
+	type notSlice struct {
+		Accounts *Account `generics:"has_many"`
+	}
+	type missingKey struct {
+		Account *Account `generics:"belongs_to"`
+	}
+	type keyType struct {
+		AccountID string
+		Account   *Account `generics:"belongs_to"`
+	}
+	type noThrough struct {
+		ID       int
+		Accounts []*Account `generics:"many_to_many"`
+	}
+	type twice struct {
+		AccountID int
+		OtherID   int
+		Account   *Account `generics:"belongs_to"`
+		Other     *Account `generics:"belongs_to"`
+	}
+	errJoinAll := JoinAllE([]*taggedAccount{}, []*Account{})
+	errThrough := JoinAllE([]*taggedAccount{}, []*taggedTag{})
+	_, errTwice := New([]*twice{}).JoinE([]*Account{})
+
+	tests := []struct {
+		Err  error
+		Want error
+	}{
+		{associationsErr(unknown{}), ErrBadTag},
+		{associationsErr(option{}), ErrBadTag},
+		{associationsErr(notSlice{}), ErrBadTag},
+		{associationsErr(missingKey{}), ErrBadTag},
+		{associationsErr(keyType{}), ErrBadTag},
+		{associationsErr(noThrough{}), ErrBadTag},
+		{errJoinAll, ErrBadSignature},
+		{errThrough, ErrBadSignature},
+		{errTwice, ErrAmbiguous},
+	}
+	for i, tc := range tests {
+		if !errors.Is(tc.Err, tc.Want) {
+			t.Errorf("%d: want %v, was %v", i+1, tc.Want, tc.Err)
+		}
+	}
+}
+
+func associationsErr(i interface{}) error {
+	_, err := Associations(reflect.TypeOf(i))
+	return err
+}
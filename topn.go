@@ -0,0 +1,148 @@
+package generics
+
+import (
+	"container/heap"
+	"reflect"
+	"sort"
+)
+
+// TopN returns the n largest elements of list by keys as accepted by Sort,
+// largest first. Ties keep their input order. It runs in O(len(list) log n)
+// and leaves list untouched. list may also be a channel, which is read until
+// it is closed:
+//
+//	TopN(payments, 10, "Amount").([]*Payment)
+func TopN(list interface{}, n int, keys ...interface{}) interface{} {
+	return must(TopNE(list, n, keys...))
+}
+
+func TopNE(list interface{}, n int, keys ...interface{}) (interface{}, error) {
+	return topNList(list, n, keys, false)
+}
+
+// BottomN returns the n smallest elements of list by keys, smallest first,
+// see TopN.
+func BottomN(list interface{}, n int, keys ...interface{}) interface{} {
+	return must(BottomNE(list, n, keys...))
+}
+
+func BottomNE(list interface{}, n int, keys ...interface{}) (interface{}, error) {
+	return topNList(list, n, keys, true)
+}
+
+func topNList(list interface{}, n int, keys []interface{}, bottom bool) (res interface{}, err error) {
+	defer catch(&err)
+	v := reflect.ValueOf(list)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	var each func(fn func(v reflect.Value))
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		each = func(fn func(v reflect.Value)) {
+			for i := 0; i < v.Len(); i++ {
+				fn(v.Index(i))
+			}
+		}
+	case reflect.Chan:
+		if v.Type().ChanDir()&reflect.RecvDir == 0 {
+			return nil, unsupportedType(v.Type(), "expected receive channel")
+		}
+		each = func(fn func(v reflect.Value)) {
+			for {
+				el, ok := v.Recv()
+				if !ok {
+					return
+				}
+				fn(el)
+			}
+		}
+	default:
+		return nil, unsupportedType(reflect.TypeOf(list), "expected slice or channel")
+	}
+	t, err := newTopHeap(v.Type().Elem(), n, keys, bottom)
+	if err != nil {
+		return nil, err
+	}
+	each(t.push)
+	return t.result().Interface(), nil
+}
+
+type topItem struct {
+	v    reflect.Value
+	keys []reflect.Value
+	seq  int
+}
+
+// topHeap keeps the best n elements pushed so far with the worst one at the
+// root, so every push is O(log n).
+type topHeap struct {
+	tp    reflect.Type
+	n     int
+	keys  []*compiledSortKey
+	items []topItem
+	seq   int
+}
+
+// newTopHeap returns a heap keeping the largest elements by keys, or the
+// smallest with bottom.
+func newTopHeap(tp reflect.Type, n int, keys []interface{}, bottom bool) (*topHeap, error) {
+	compiled, err := compileSortKeys(tp, keys, !bottom)
+	if err != nil {
+		return nil, err
+	}
+	return &topHeap{tp: tp, n: n, keys: compiled}, nil
+}
+
+// better orders items by their keys, ties by input order.
+func (t *topHeap) better(a, b topItem) bool {
+	for k, key := range t.keys {
+		if c := key.compare(a.keys[k], b.keys[k]); c != 0 {
+			return c < 0
+		}
+	}
+	return a.seq < b.seq
+}
+
+func (t *topHeap) push(v reflect.Value) {
+	if t.n <= 0 {
+		return
+	}
+	item := topItem{v: v, keys: make([]reflect.Value, len(t.keys)), seq: t.seq}
+	t.seq++
+	for k, key := range t.keys {
+		item.keys[k] = key.getter(v)
+	}
+	switch {
+	case len(t.items) < t.n:
+		heap.Push(t, item)
+	case t.better(item, t.items[0]):
+		t.items[0] = item
+		heap.Fix(t, 0)
+	}
+}
+
+// result returns the kept elements, best first.
+func (t *topHeap) result() reflect.Value {
+	items := append([]topItem(nil), t.items...)
+	sort.Slice(items, func(a, b int) bool { return t.better(items[a], items[b]) })
+	out := reflect.MakeSlice(reflect.SliceOf(t.tp), len(items), len(items))
+	for i, item := range items {
+		out.Index(i).Set(item.v)
+	}
+	return out
+}
+
+func (t *topHeap) Len() int { return len(t.items) }
+
+func (t *topHeap) Less(a, b int) bool { return t.better(t.items[b], t.items[a]) }
+
+func (t *topHeap) Swap(a, b int) { t.items[a], t.items[b] = t.items[b], t.items[a] }
+
+func (t *topHeap) Push(x interface{}) { t.items = append(t.items, x.(topItem)) }
+
+func (t *topHeap) Pop() interface{} {
+	item := t.items[len(t.items)-1]
+	t.items = t.items[:len(t.items)-1]
+	return item
+}
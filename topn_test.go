@@ -0,0 +1,62 @@
+package generics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func amounts(list interface{}) string {
+	return fmt.Sprint(Map(list, "Amount"))
+}
+
+func TestTopN(t *testing.T) {
+	list := append(groupedPayments(), &groupedPayment{2, "EUR", 20})
+	ch := make(chan *groupedPayment, len(list))
+	for _, p := range list {
+		ch <- p
+	}
+	close(ch)
+	grouped := New(list).GroupBy("AccountID").TopN(1, "Amount").Groups()
+
+	tests := []struct{ Has, Want interface{} }{
+		{amounts(TopN(list, 3, "Amount")), "[40 30 20]"},
+		{TopN(list, 3, "Amount").([]*groupedPayment)[2].AccountID, 1},
+		{amounts(BottomN(list, 2, "Amount")), "[1 5]"},
+		{amounts(TopN(list, 10, "Amount")), "[40 30 20 20 10 5 1]"},
+		{amounts(TopN(list, 0, "Amount")), "[]"},
+		{amounts(TopN(list, 2, "Currency", "-Amount")), "[5 30]"},
+		{amounts(list), "[10 20 5 30 40 1 20]"},
+		{amounts(TopN(ch, 2, "Amount")), "[40 30]"},
+		{amounts(New(list).BottomN(3, "Amount").Cast()), "[1 5 10]"},
+		{amounts(New(list).Lazy().Select(func(p *groupedPayment) bool { return p.Currency == "EUR" }).TopN(2, "Amount").Cast()), "[40 20]"},
+		{amounts(New(list).Lazy().BottomN(1, "Amount").Cast()), "[1]"},
+		{amounts(grouped[0].Value), "[30]"},
+		{amounts(grouped[1].Value), "[20]"},
+		{amounts(grouped[2].Value), "[40]"},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestTopNErrors(t *testing.T) {
+	_, errList := TopNE(1, 2, "Amount")
+	_, errKey := TopNE(groupedPayments(), 2, "Missing")
+	_, errKeys := BottomNE(groupedPayments(), 2)
+	_, errChan := TopNE(make(chan<- int), 2, "Amount")
+
+	tests := []struct{ Has, Want interface{} }{
+		{errors.Is(errList, ErrUnsupportedType), true},
+		{errors.Is(errKey, ErrFieldNotFound), true},
+		{errors.Is(errKeys, ErrBadSignature), true},
+		{errors.Is(errChan, ErrUnsupportedType), true},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
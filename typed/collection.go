@@ -0,0 +1,81 @@
+package typed
+
+import "cmp"
+
+func New[T any](list []T) *Collection[T] {
+	return &Collection[T]{list}
+}
+
+// Collection is the typed counterpart of generics.Collection. Go methods
+// cannot introduce type parameters, so operations changing the element type
+// (Map, FoldLeft, GroupBy, Index) are only available as functions:
+//
+//	names := typed.Map(c.Cast(), func(r *Record) string { return r.Name })
+type Collection[T any] struct {
+	collection []T
+}
+
+func (c *Collection[T]) Select(fn func(T) bool) *Collection[T] {
+	return New(Select(c.collection, fn))
+}
+
+func (c *Collection[T]) Reject(fn func(T) bool) *Collection[T] {
+	return New(Reject(c.collection, fn))
+}
+
+func (c *Collection[T]) First() T {
+	return First(c.collection)
+}
+
+func (c *Collection[T]) Last() T {
+	return Last(c.collection)
+}
+
+func (c *Collection[T]) FirstN(n int) *Collection[T] {
+	return New(FirstN(c.collection, n))
+}
+
+func (c *Collection[T]) LastN(n int) *Collection[T] {
+	return New(LastN(c.collection, n))
+}
+
+func (c *Collection[T]) Cast() []T {
+	return c.collection
+}
+
+// Sort sorts the collection in place using less. To sort by a key use SortBy:
+//
+//	typed.SortBy(c, func(r *Record) int { return r.Amount })
+func (c *Collection[T]) Sort(less func(a, b T) bool) *Collection[T] {
+	sortFunc(c.collection, less)
+	return c
+}
+
+func (c *Collection[T]) SortReverse(less func(a, b T) bool) *Collection[T] {
+	sortFunc(c.collection, func(a, b T) bool { return less(b, a) })
+	return c
+}
+
+func (c *Collection[T]) SortKeys(keys ...Key[T]) *Collection[T] {
+	SortKeys(c.collection, keys...)
+	return c
+}
+
+func (c *Collection[T]) SortStable(keys ...Key[T]) *Collection[T] {
+	SortStable(c.collection, keys...)
+	return c
+}
+
+func (c *Collection[T]) Len() int {
+	return len(c.collection)
+}
+
+func SortBy[T any, K cmp.Ordered](c *Collection[T], fn func(T) K) *Collection[T] {
+	Sort(c.collection, fn)
+	return c
+}
+
+func SortReverseBy[T any, K cmp.Ordered](c *Collection[T], fn func(T) K) *Collection[T] {
+	SortReverse(c.collection, fn)
+	return c
+}
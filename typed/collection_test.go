@@ -0,0 +1,39 @@
+package typed
+
+import "testing"
+
+func TestCollection(t *testing.T) {
+	list := []*record{
+		{"zero", 0},
+		{"one", 1},
+		{"two", 2},
+		{"three", 3},
+	}
+	c := New(list).Select(func(r *record) bool { return r.Amount < 3 })
+	c = SortReverseBy(c, func(r *record) int { return r.Amount }).FirstN(2)
+
+	if v := c.Len(); v != 2 {
+		t.Errorf("%d", v)
+	}
+
+	if v := c.First().Name; v != "two" {
+		t.Errorf("%s", v)
+	}
+
+	if v := c.Last().Name; v != "one" {
+		t.Errorf("%s", v)
+	}
+
+	m := FoldLeft(c.Cast(), func(m map[string]int, v *record) map[string]int {
+		m[v.Name] = v.Amount * v.Amount
+		return m
+	})
+	if v := m["two"]; v != 4 {
+		t.Errorf("%d", v)
+	}
+
+	c.Sort(func(a, b *record) bool { return a.Name < b.Name })
+	if v := c.First().Name; v != "one" {
+		t.Errorf("%s", v)
+	}
+}
@@ -0,0 +1,65 @@
+package typed
+
+import (
+	"cmp"
+	"sort"
+)
+
+func Sort[T any, K cmp.Ordered](list []T, fn func(T) K) {
+	sort.Slice(list, func(a, b int) bool {
+		return fn(list[a]) < fn(list[b])
+	})
+}
+
+func sortFunc[T any](list []T, less func(a, b T) bool) {
+	sort.Slice(list, func(a, b int) bool {
+		return less(list[a], list[b])
+	})
+}
+
+func SortReverse[T any, K cmp.Ordered](list []T, fn func(T) K) {
+	sort.Slice(list, func(a, b int) bool {
+		return fn(list[b]) < fn(list[a])
+	})
+}
+
+// Key compares two elements by one sort key, see Asc and Desc.
+type Key[T any] func(a, b T) int
+
+func Asc[T any, K cmp.Ordered](fn func(T) K) Key[T] {
+	return func(a, b T) int {
+		return cmp.Compare(fn(a), fn(b))
+	}
+}
+
+func Desc[T any, K cmp.Ordered](fn func(T) K) Key[T] {
+	return func(a, b T) int {
+		return cmp.Compare(fn(b), fn(a))
+	}
+}
+
+// SortKeys sorts list in place by the given keys, the first key taking
+// precedence:
+//
+//	typed.SortKeys(payments, typed.Asc(accountID), typed.Desc(amount))
+func SortKeys[T any](list []T, keys ...Key[T]) {
+	sort.Slice(list, func(a, b int) bool {
+		return compareKeys(list[a], list[b], keys) < 0
+	})
+}
+
+// SortStable sorts like SortKeys but keeps the input order of equal elements.
+func SortStable[T any](list []T, keys ...Key[T]) {
+	sort.SliceStable(list, func(a, b int) bool {
+		return compareKeys(list[a], list[b], keys) < 0
+	})
+}
+
+func compareKeys[T any](a, b T, keys []Key[T]) int {
+	for _, k := range keys {
+		if c := k(a, b); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
@@ -0,0 +1,32 @@
+package typed
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSortKeys(t *testing.T) {
+	list := []*record{
+		{"b", 1},
+		{"c", 2},
+		{"a", 1},
+		{"d", 2},
+	}
+	name := func(r *record) string { return r.Name }
+	amount := func(r *record) int { return r.Amount }
+
+	SortKeys(list, Desc(amount), Asc(name))
+	if has, want := fmt.Sprint(names(list)), "[c d a b]"; has != want {
+		t.Errorf("want %s, was %s", want, has)
+	}
+
+	SortStable(list, Asc(amount))
+	if has, want := fmt.Sprint(names(list)), "[a b c d]"; has != want {
+		t.Errorf("want %s, was %s", want, has)
+	}
+
+	New(list).SortKeys(Desc(name))
+	if has, want := fmt.Sprint(names(list)), "[d c b a]"; has != want {
+		t.Errorf("want %s, was %s", want, has)
+	}
+}
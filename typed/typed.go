@@ -0,0 +1,205 @@
+// Package typed is the type-parameterized companion of package generics.
+//
+// Every function mirrors its reflection based counterpart in generics with
+// identical semantics, but the element and key types are checked by the
+// compiler instead of at runtime, so no type assertions are needed:
+//
+//	first := typed.First(list) // *Thing, not interface{}
+//
+// Attribute lookups by name ("Amount") are only available in generics.
+package typed
+
+import (
+	"math"
+	"reflect"
+	"slices"
+)
+
+// Number is satisfied by all integer and float types, including named ones.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+func Map[T, U any](list []T, fn func(T) U) []U {
+	out := make([]U, 0, len(list))
+	for _, v := range list {
+		out = append(out, fn(v))
+	}
+	return out
+}
+
+func Values[K comparable, V any](m map[K]V) []V {
+	out := make([]V, 0, len(m))
+	for _, v := range m {
+		out = append(out, v)
+	}
+	return out
+}
+
+func Keys[K comparable, V any](m map[K]V) []K {
+	out := make([]K, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+// FoldLeft folds ... left
+//
+//	FoldLeft([]int{1, 2, 3}, func(list []int, value int) []int {
+//	  return append(list, value * value)
+//	}) => [1, 4, 9]
+//
+// Like generics.FoldLeft the accumulator starts out as an empty map, an empty
+// slice, a pointer to a new struct or the zero value, depending on A.
+func FoldLeft[T, A any](list []T, folder func(A, T) A) A {
+	acc := initial[A]()
+	for _, v := range list {
+		acc = folder(acc, v)
+	}
+	return acc
+}
+
+func initial[A any]() (acc A) {
+	v := reflect.ValueOf(&acc).Elem()
+	switch t := v.Type(); t.Kind() {
+	case reflect.Map:
+		v.Set(reflect.MakeMap(t))
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(t, 0, 0))
+	case reflect.Ptr:
+		if t.Elem().Kind() == reflect.Struct {
+			v.Set(reflect.New(t.Elem()))
+		}
+	}
+	return acc
+}
+
+func GroupBy[T any, K comparable](list []T, fn func(T) K) map[K][]T {
+	m := map[K][]T{}
+	for _, v := range list {
+		k := fn(v)
+		m[k] = append(m[k], v)
+	}
+	return m
+}
+
+func Index[T any, K comparable](list []T, fn func(T) K) map[K]T {
+	m := make(map[K]T, len(list))
+	for _, v := range list {
+		m[fn(v)] = v
+	}
+	return m
+}
+
+func Reject[T any](list []T, rejecter func(T) bool) []T {
+	return Select(list, func(v T) bool { return !rejecter(v) })
+}
+
+func Filter[T any](list []T, filter func(T) bool) []T {
+	return Select(list, filter)
+}
+
+func Select[T any](list []T, filter func(T) bool) []T {
+	out := []T{}
+	for _, v := range list {
+		if filter(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func Last[T any](list []T) (last T) {
+	if len(list) == 0 {
+		return last
+	}
+	return list[len(list)-1]
+}
+
+func First[T any](list []T) (first T) {
+	if len(list) == 0 {
+		return first
+	}
+	return list[0]
+}
+
+func FirstN[T any](list []T, n int) []T {
+	n = clamp(n, len(list))
+	out := make([]T, n)
+	copy(out, list[:n])
+	return out
+}
+
+func LastN[T any](list []T, n int) []T {
+	n = clamp(n, len(list))
+	out := make([]T, n)
+	copy(out, list[len(list)-n:])
+	return out
+}
+
+func clamp(n, l int) int {
+	if n > l {
+		return l
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// Max returns the largest number in list, or NaN if list is empty.
+func Max[N Number](list []N) float64 {
+	return extreme(list, func(a, b N) bool { return a > b })
+}
+
+// Min returns the smallest number in list, or NaN if list is empty.
+func Min[N Number](list []N) float64 {
+	return extreme(list, func(a, b N) bool { return a < b })
+}
+
+func extreme[N Number](list []N, better func(a, b N) bool) float64 {
+	if len(list) == 0 {
+		return math.NaN()
+	}
+	res := list[0]
+	for _, v := range list[1:] {
+		if better(v, res) {
+			res = v
+		}
+	}
+	return float64(res)
+}
+
+func Sum[N Number](list []N) (sum float64) {
+	for _, v := range list {
+		sum += float64(v)
+	}
+	return sum
+}
+
+// Average returns the arithmetic mean of list, or NaN if list is empty.
+func Average[N Number](list []N) float64 {
+	if len(list) == 0 {
+		return math.NaN()
+	}
+	return Sum(list) / float64(len(list))
+}
+
+// Percentile returns the perc percentile of list by nearest rank, or NaN if
+// list is empty or perc is not within [0, 100].
+func Percentile[N Number](list []N, perc int) float64 {
+	if len(list) == 0 || perc < 0 || perc > 100 {
+		return math.NaN()
+	}
+	values := make([]N, len(list))
+	copy(values, list)
+	slices.Sort(values)
+	rank := (perc*len(values) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return float64(values[rank-1])
+}
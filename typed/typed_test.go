@@ -0,0 +1,123 @@
+package typed
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/phrase/generics"
+)
+
+type record struct {
+	Name   string
+	Amount int
+}
+
+func records() []*record {
+	return []*record{
+		{Name: "one", Amount: 1},
+		{Name: "two", Amount: 1},
+		{Name: "three", Amount: 2},
+	}
+}
+
+// The same cases run against the typed and the reflection API, so both stay
+// in sync.
+func TestSharedWithReflection(t *testing.T) {
+	name := func(r *record) string { return r.Name }
+	amount := func(r *record) int { return r.Amount }
+	big := func(r *record) bool { return r.Amount >= 2 }
+	ints := []int{1, 2, 3}
+
+	tests := []struct {
+		Name        string
+		Typed, Refl interface{}
+		Want        string
+	}{
+		{"Map", Map(records(), name), generics.Map(records(), name), "[one two three]"},
+		{"Select", names(Select(records(), big)), names(generics.Select(records(), big).([]*record)), "[three]"},
+		{"Filter", names(Filter(records(), big)), names(generics.Filter(records(), big).([]*record)), "[three]"},
+		{"Reject", names(Reject(records(), big)), names(generics.Reject(records(), big).([]*record)), "[one two]"},
+		{"First", First(records()).Name, generics.First(records()).(*record).Name, "one"},
+		{"First empty", First([]*record{}), generics.First([]*record{}), "<nil>"},
+		{"First empty string", First([]string{}), generics.First([]string{}), ""},
+		{"Last", Last(records()).Name, generics.Last(records()).(*record).Name, "three"},
+		{"FirstN", FirstN([]int{1, 2, 3}, 2), generics.FirstN([]int{1, 2, 3}, 2), "[1 2]"},
+		{"FirstN too many", FirstN([]int{1}, 5), generics.FirstN([]int{1}, 5), "[1]"},
+		{"FirstN negative", FirstN([]int{1}, -1), generics.FirstN([]int{1}, -1), "[]"},
+		{"LastN", LastN([]int{1, 2, 3}, 2), generics.LastN([]int{1, 2, 3}, 2), "[2 3]"},
+		{"LastN negative", LastN([]int{1}, -1), generics.LastN([]int{1}, -1), "[]"},
+		{"FoldLeft sum", FoldLeft(ints, func(s, v int) int { return s + v }), generics.FoldLeft(ints, func(s, v int) int { return s + v }), "6"},
+		{"FoldLeft map", FoldLeft(ints, func(m map[int]int, v int) map[int]int { m[v] = v * v; return m }),
+			generics.FoldLeft(ints, func(m map[int]int, v int) map[int]int { m[v] = v * v; return m }), "map[1:1 2:4 3:9]"},
+		{"Group", len(GroupBy(records(), amount)[1]), len(generics.Group(records(), amount).(map[int][]*record)[1]), "2"},
+		{"Index", Index(records(), name)["two"].Amount, generics.Index(records(), name).(map[string]*record)["two"].Amount, "1"},
+		{"Sum", Sum(ints), generics.Sum(ints), "6"},
+		{"Max", Max(ints), generics.Max(ints), "3"},
+		{"Min", Min(ints), generics.Min(ints), "1"},
+		{"Min negative", Min([]int{-5, -3}), generics.Min([]int{-5, -3}), "-5"},
+		{"Max negative", Max([]int{-5, -3}), generics.Max([]int{-5, -3}), "-3"},
+		{"Max empty", Max([]int{}), generics.Max([]int{}), "NaN"},
+		{"Average empty", Average([]int{}), generics.Average([]int{}), "NaN"},
+		{"Average", Average(ints), generics.Average(ints), "2"},
+		{"Percentile", Percentile(ints, 50), generics.Percentile(ints, 50), "2"},
+		{"Percentile unsorted", Percentile([]int{3, 1, 2}, 100), generics.Percentile([]int{3, 1, 2}, 100), "3"},
+		{"Percentile empty", Percentile([]int{}, 50), generics.Percentile([]int{}, 50), "NaN"},
+	}
+	for _, tc := range tests {
+		if has := fmt.Sprintf("%v", tc.Typed); has != tc.Want {
+			t.Errorf("%s: want %q, was %q", tc.Name, tc.Want, has)
+		}
+		if has := fmt.Sprintf("%v", tc.Refl); has != tc.Want {
+			t.Errorf("%s: want %q from reflection, was %q", tc.Name, tc.Want, has)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	list := records()
+	SortReverse(list, func(r *record) int { return r.Amount })
+	if has := list[0].Name; has != "three" {
+		t.Errorf("has was %q", has)
+	}
+	Sort(list, func(r *record) string { return r.Name })
+	if has := fmt.Sprint(names(list)); has != "[one three two]" {
+		t.Errorf("has was %q", has)
+	}
+}
+
+func TestKeysAndValues(t *testing.T) {
+	m := map[string]int{"one": 1, "two": 2, "three": 3}
+
+	keys := Keys(m)
+	sort.Strings(keys)
+	values := Values(m)
+	sort.Ints(values)
+
+	tests := []struct{ Has, Want interface{} }{
+		{fmt.Sprint(keys), "[one three two]"},
+		{fmt.Sprint(values), "[1 2 3]"},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestFoldLeftWithStruct(t *testing.T) {
+	type stat struct {
+		Sum int
+	}
+	res := FoldLeft([]int{1, 2, 3}, func(s *stat, value int) *stat {
+		s.Sum += value
+		return s
+	})
+	if res.Sum != 6 {
+		t.Errorf("sum is %d", res.Sum)
+	}
+}
+
+func names(list []*record) []string {
+	return Map(list, func(r *record) string { return r.Name })
+}
@@ -0,0 +1,209 @@
+package generics
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// WindowRow is the result of a window function for one element.
+type WindowRow struct {
+	Element interface{}
+	Value   interface{}
+}
+
+// Window splits a list into partitions and orders every partition, like
+// SQL's OVER (PARTITION BY ... ORDER BY ...). Every window function returns
+// one WindowRow per element, partitions in key order as by GroupBy and
+// elements in window order:
+//
+//	w := NewWindow(payments, "AccountID", "-Amount")
+//	for _, row := range w.Rank() {
+//		fmt.Println(row.Element.(*Payment).ID, row.Value.(int))
+//	}
+type Window struct {
+	elemType   reflect.Type
+	order      []*compiledSortKey
+	partitions []reflect.Value
+}
+
+// NewWindow partitions list by partitionBy, an attribute or func as accepted
+// by Group or nil for a single partition, and sorts every partition stably by
+// the orderBy keys as accepted by Sort. Without orderBy the input order is
+// kept. list itself is not modified.
+func NewWindow(list interface{}, partitionBy interface{}, orderBy ...interface{}) *Window {
+	return must(NewWindowE(list, partitionBy, orderBy...)).(*Window)
+}
+
+func NewWindowE(list interface{}, partitionBy interface{}, orderBy ...interface{}) (res *Window, err error) {
+	defer catch(&err)
+	v, err := sliceValue(list)
+	if err != nil {
+		return nil, err
+	}
+	w := &Window{elemType: v.Type().Elem()}
+	if len(orderBy) > 0 {
+		if w.order, err = compileSortKeys(w.elemType, orderBy, false); err != nil {
+			return nil, err
+		}
+	}
+	// a copy, so sorting does not modify list, which may also be an array
+	all := reflect.MakeSlice(reflect.SliceOf(w.elemType), v.Len(), v.Len())
+	reflect.Copy(all, v)
+	if partitionBy == nil {
+		w.partitions = []reflect.Value{all}
+	} else {
+		w.partitions = New(all.Interface()).GroupBy(partitionBy).groups
+	}
+	if len(orderBy) > 0 {
+		for _, p := range w.partitions {
+			if err := SortStableE(p.Interface(), orderBy...); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return w, nil
+}
+
+// RowNumber numbers the elements of every partition from 1.
+func (w *Window) RowNumber() []WindowRow {
+	return w.each(func(part int, p reflect.Value, i int) interface{} {
+		return i + 1
+	})
+}
+
+// Rank numbers the elements of every partition from 1, giving ties on the
+// order keys the same rank and skipping the ranks after them.
+func (w *Window) Rank() []WindowRow {
+	var rank int
+	return w.each(func(part int, p reflect.Value, i int) interface{} {
+		if i == 0 || !w.tie(p, i) {
+			rank = i + 1
+		}
+		return rank
+	})
+}
+
+// DenseRank ranks like Rank without gaps after ties.
+func (w *Window) DenseRank() []WindowRow {
+	var rank int
+	return w.each(func(part int, p reflect.Value, i int) interface{} {
+		if i == 0 {
+			rank = 0
+		}
+		if i == 0 || !w.tie(p, i) {
+			rank++
+		}
+		return rank
+	})
+}
+
+// Lag returns key, an attribute or func as accepted by Map, of the element n
+// rows before every element in its partition, or nil if there is none.
+func (w *Window) Lag(key interface{}, n int) []WindowRow {
+	return w.offset(key, -n)
+}
+
+// Lead returns key of the element n rows after every element, see Lag.
+func (w *Window) Lead(key interface{}, n int) []WindowRow {
+	return w.offset(key, n)
+}
+
+func (w *Window) offset(key interface{}, n int) []WindowRow {
+	_, getter, err := newGetter(w.elemType, key)
+	if err != nil {
+		panic(err)
+	}
+	return w.each(func(part int, p reflect.Value, i int) interface{} {
+		if i+n < 0 || i+n >= p.Len() {
+			return nil
+		}
+		return getter(p.Index(i + n)).Interface()
+	})
+}
+
+// CumulativeSum returns the running total of key, a number, up to and
+// including every element of its partition.
+func (w *Window) CumulativeSum(key interface{}) []WindowRow {
+	values := w.floats(key)
+	var sum float64
+	return w.each(func(part int, p reflect.Value, i int) interface{} {
+		if i == 0 {
+			sum = 0
+		}
+		if f := values[part][i]; !math.IsNaN(f) {
+			sum += f
+		}
+		return sum
+	})
+}
+
+// MovingAverage returns the mean of key over every element and up to n-1
+// elements before it in its partition. Nil keys are left out, the average of
+// none is NaN.
+func (w *Window) MovingAverage(key interface{}, n int) []WindowRow {
+	if n < 1 {
+		panic(&Error{Kind: ErrIndexOutOfRange, Type: w.elemType, Msg: fmt.Sprintf("window size must be positive, was %d", n)})
+	}
+	values := w.floats(key)
+	return w.each(func(part int, p reflect.Value, i int) interface{} {
+		var sum float64
+		var count int
+		for _, f := range values[part][clamp(i-n+1, i) : i+1] {
+			if !math.IsNaN(f) {
+				sum += f
+				count++
+			}
+		}
+		if count == 0 {
+			return math.NaN()
+		}
+		return sum / float64(count)
+	})
+}
+
+// floats converts key of every element to a number, NaN for nil, by
+// partition.
+func (w *Window) floats(key interface{}) [][]float64 {
+	_, getter, err := newGetter(w.elemType, key)
+	if err != nil {
+		panic(err)
+	}
+	res := make([][]float64, len(w.partitions))
+	for part, p := range w.partitions {
+		values := make([]float64, p.Len())
+		for i := range values {
+			f, ok, err := toFloat(getter(p.Index(i)), &numberOptions{})
+			if err != nil {
+				panic(elementError(err, i))
+			}
+			if !ok {
+				f = math.NaN()
+			}
+			values[i] = f
+		}
+		res[part] = values
+	}
+	return res
+}
+
+// tie reports whether element i of p equals the one before on all order
+// keys. Without order keys all elements are ties.
+func (w *Window) tie(p reflect.Value, i int) bool {
+	for _, k := range w.order {
+		if k.compare(k.getter(p.Index(i-1)), k.getter(p.Index(i))) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *Window) each(fn func(part int, p reflect.Value, i int) interface{}) []WindowRow {
+	var res []WindowRow
+	for part, p := range w.partitions {
+		for i := 0; i < p.Len(); i++ {
+			res = append(res, WindowRow{Element: p.Index(i).Interface(), Value: fn(part, p, i)})
+		}
+	}
+	return res
+}
@@ -0,0 +1,88 @@
+package generics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func windowString(rows []WindowRow) string {
+	s := ""
+	for _, r := range rows {
+		p := r.Element.(*groupedPayment)
+		s += fmt.Sprintf("%d/%v:%v ", p.AccountID, p.Amount, r.Value)
+	}
+	return s
+}
+
+func TestWindow(t *testing.T) {
+	list := append(groupedPayments(), &groupedPayment{1, "EUR", 20})
+	byAmount := NewWindow(list, "AccountID", "-Amount")
+	byCurrency := New(list).Window("AccountID", "Currency")
+	all := NewWindow(list, nil)
+
+	tests := []struct{ Has, Want interface{} }{
+		{windowString(byAmount.RowNumber()), "1/30:1 1/20:2 1/20:3 1/1:4 2/5:1 3/40:1 3/10:2 "},
+		{windowString(byAmount.Rank()), "1/30:1 1/20:2 1/20:2 1/1:4 2/5:1 3/40:1 3/10:2 "},
+		{windowString(byAmount.DenseRank()), "1/30:1 1/20:2 1/20:2 1/1:3 2/5:1 3/40:1 3/10:2 "},
+		{windowString(byCurrency.Rank()), "1/20:1 1/1:1 1/20:1 1/30:4 2/5:1 3/10:1 3/40:1 "},
+		{windowString(byAmount.Lag("Amount", 1)), "1/30:<nil> 1/20:30 1/20:20 1/1:20 2/5:<nil> 3/40:<nil> 3/10:40 "},
+		{windowString(byAmount.Lead(func(p *groupedPayment) string { return p.Currency }, 2)), "1/30:EUR 1/20:EUR 1/20:<nil> 1/1:<nil> 2/5:<nil> 3/40:<nil> 3/10:<nil> "},
+		{windowString(byAmount.CumulativeSum("Amount")), "1/30:30 1/20:50 1/20:70 1/1:71 2/5:5 3/40:40 3/10:50 "},
+		{windowString(byAmount.MovingAverage("Amount", 2)), "1/30:30 1/20:25 1/20:20 1/1:10.5 2/5:5 3/40:40 3/10:25 "},
+		{windowString(all.CumulativeSum("Amount")), "3/10:10 1/20:30 2/5:35 1/30:65 3/40:105 1/1:106 1/20:126 "},
+		{windowString(all.Rank()), "3/10:1 1/20:1 2/5:1 1/30:1 3/40:1 1/1:1 1/20:1 "},
+		{list[0].Amount, 10.0},
+		{windowString(NewWindow([2]*groupedPayment{list[0], list[1]}, nil, "Amount").RowNumber()), "3/10:1 1/20:2 "},
+		{windowString(NewWindow([2]*groupedPayment{list[0], list[1]}, "AccountID").RowNumber()), "1/20:1 3/10:1 "},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+}
+
+func TestWindowErrors(t *testing.T) {
+	_, errList := NewWindowE(1, nil)
+	_, errPartition := NewWindowE(groupedPayments(), "Missing")
+	_, errOrder := NewWindowE(groupedPayments(), nil, "Missing")
+
+	tests := []struct{ Has, Want interface{} }{
+		{errors.Is(errList, ErrUnsupportedType), true},
+		{errors.Is(errPartition, ErrFieldNotFound), true},
+		{errors.Is(errOrder, ErrFieldNotFound), true},
+	}
+	for i, tc := range tests {
+		if tc.Want != tc.Has {
+			t.Errorf("%d: want %#v (%T), was %#v (%T)", i+1, tc.Want, tc.Want, tc.Has, tc.Has)
+		}
+	}
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("want ErrIndexOutOfRange, was %v", err)
+		}
+	}()
+	NewWindow(groupedPayments(), nil).MovingAverage("Amount", 0)
+}
+
+func TestWindowPointerPartitions(t *testing.T) {
+	type payment struct {
+		Account *Account
+		Amount  float64
+	}
+	b, a := &Account{Name: "b"}, &Account{Name: "a"}
+	list := []*payment{{b, 1}, {a, 2}, {b, 3}, {nil, 4}}
+	w, err := NewWindowE(list, "Account", "-Amount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := ""
+	for _, r := range w.RowNumber() {
+		s += fmt.Sprintf("%v:%v ", r.Element.(*payment).Amount, r.Value)
+	}
+	if want := "3:1 1:2 2:1 4:1 "; s != want {
+		t.Errorf("want %q, was %q", want, s)
+	}
+}